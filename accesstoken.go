@@ -0,0 +1,172 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AccessTokenType is the "typ" header of JWT access tokens (RFC 9068).
+const AccessTokenType = "at+jwt"
+
+// AccessTokenOption represents the options of SignAccessToken.
+type AccessTokenOption struct {
+	Algorithm Algorithm
+	// Issuer, Audience, Subject, ClientID and ExpiresIn are required.
+	Issuer   string
+	Audience []string
+	Subject  string
+	ClientID string
+	// ExpiresIn specifies the lifetime of the token.
+	ExpiresIn time.Duration
+	// ID specifies the "jti" claim, a random one will be generated if it is
+	// empty.
+	ID string
+	// Scope specifies the scopes granted, which will be joined by spaces.
+	Scope []string
+	// AuthTime, ACR and AMR specify the authentication of the subject if they
+	// are not empty.
+	AuthTime time.Time
+	ACR      string
+	AMR      []string
+	// JKT specifies the thumbprint of the DPoP key which the token is bound
+	// to in the "cnf" claim if it is not empty.
+	JKT string
+	// Header is the customized header which will be merged to token's header.
+	Header Header
+	// Clock returns the time used as "iat" of the token, time.Now will be
+	// used if it is nil.
+	Clock func() time.Time
+}
+
+// accessTokenClaims are the rules of the claims required by RFC 9068 section
+// 2.2.
+var accessTokenClaims = []ClaimRule{
+	{Name: "iss", Required: true, Type: ClaimString},
+	{Name: "exp", Required: true, Type: ClaimNumber},
+	{Name: "aud", Required: true},
+	{Name: "sub", Required: true, Type: ClaimString},
+	{Name: "client_id", Required: true, Type: ClaimString},
+	{Name: "iat", Required: true, Type: ClaimNumber},
+	{Name: "jti", Required: true, Type: ClaimString},
+	{Name: "scope", Type: ClaimString},
+	{Name: "auth_time", Type: ClaimNumber},
+	{Name: "acr", Type: ClaimString},
+	{Name: "amr", Type: ClaimArray},
+	{Name: "cnf", Type: ClaimObject},
+}
+
+// SignAccessToken signs the given payload to an RFC 9068 access token with
+// "typ" at+jwt and the claims of opt. Unlike Sign, "exp" is the absolute
+// expiration time as the profile requires, so the token should be verified
+// by VerifyAccessToken.
+func SignAccessToken(payload Payload, secretOrPrivateKey interface{}, opt *AccessTokenOption) ([]byte, error) {
+	if opt == nil {
+		opt = &AccessTokenOption{}
+	}
+
+	if opt.Issuer == "" || len(opt.Audience) == 0 || opt.Subject == "" || opt.ClientID == "" || opt.ExpiresIn <= 0 {
+		return nil, fmt.Errorf("%w: iss, aud, sub, client_id and exp are required", ErrInvalidAccessToken)
+	}
+
+	now := currentTime(opt.Clock)
+	claims := Payload{
+		"iss":       opt.Issuer,
+		"sub":       opt.Subject,
+		"client_id": opt.ClientID,
+		"exp":       now.Add(opt.ExpiresIn).Unix(),
+		"jti":       opt.ID,
+	}
+
+	if len(opt.Audience) == 1 {
+		claims["aud"] = opt.Audience[0]
+	} else {
+		claims["aud"] = opt.Audience
+	}
+
+	if opt.ID == "" {
+		id, err := randomID()
+
+		if err != nil {
+			return nil, err
+		}
+
+		claims["jti"] = id
+	}
+
+	if len(opt.Scope) > 0 {
+		claims["scope"] = strings.Join(opt.Scope, " ")
+	}
+
+	if opt.JKT != "" {
+		claims["cnf"] = map[string]interface{}{"jkt": opt.JKT}
+	}
+
+	if !opt.AuthTime.IsZero() {
+		claims["auth_time"] = opt.AuthTime.Unix()
+	}
+
+	if opt.ACR != "" {
+		claims["acr"] = opt.ACR
+	}
+
+	if len(opt.AMR) > 0 {
+		claims["amr"] = opt.AMR
+	}
+
+	for k, v := range payload {
+		if _, ok := claims[k]; !ok {
+			claims[k] = v
+		}
+	}
+
+	return Sign(claims, secretOrPrivateKey, &SignOption{
+		Algorithm: opt.Algorithm,
+		Type:      AccessTokenType,
+		Header:    opt.Header,
+		Clock:     func() time.Time { return now },
+	})
+}
+
+// randomID returns a random base64url encoded 128-bit identifier for "jti".
+func randomID() (string, error) {
+	id := make([]byte, 16)
+
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
+
+// VerifyAccessToken is like Verify, but validates the token according to RFC
+// 9068 section 4: "typ" must be at+jwt, the required claims must be present,
+// "exp" is the absolute expiration time, and opt.Issuer and opt.Audience are
+// required, the latter matching any of the audiences of the token.
+func VerifyAccessToken(token []byte, secretOrPrivateKey interface{}, opt *VerifyOption) (header Header, payload Payload, err error) {
+	return VerifyAccessTokenContext(context.Background(), token, secretOrPrivateKey, opt)
+}
+
+// VerifyAccessTokenContext is like VerifyAccessToken, but passes ctx to the
+// Validators of opt.
+func VerifyAccessTokenContext(ctx context.Context, token []byte, secretOrPrivateKey interface{}, opt *VerifyOption) (header Header, payload Payload, err error) {
+	var o VerifyOption
+
+	if opt != nil {
+		o = *opt
+	}
+
+	if o.Issuer == "" || o.Audience == "" {
+		return nil, nil, fmt.Errorf("%w: expected issuer and audience are required", ErrInvalidAccessToken)
+	}
+
+	o.Types = []string{AccessTokenType}
+	o.AllowMissingType = false
+	o.IngoreExpiration, o.AbsoluteExpiration = false, true
+	o.Claims = append(append([]ClaimRule{}, accessTokenClaims...), o.Claims...)
+
+	return VerifyContext(ctx, token, secretOrPrivateKey, &o)
+}
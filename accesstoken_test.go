@@ -0,0 +1,161 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessToken(t *testing.T) {
+	assert := assert.New(t)
+
+	opt := &AccessTokenOption{
+		Issuer:    "https://as.example.com/",
+		Audience:  []string{"https://rs.example.com/", "https://other.example.com/"},
+		Subject:   "alice",
+		ClientID:  "client",
+		ExpiresIn: time.Hour,
+		Scope:     []string{"read", "write"},
+		AuthTime:  time.Unix(1000, 0),
+		AMR:       []string{"pwd"},
+		JKT:       "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I",
+	}
+
+	verifyOpt := &VerifyOption{Issuer: "https://as.example.com/", Audience: "https://rs.example.com/"}
+
+	t.Run("Should sign and verify access token", func(t *testing.T) {
+		token, err := SignAccessToken(Payload{"tenant": "acme", "sub": "mallory"}, "key", opt)
+
+		assert.Nil(err)
+
+		header, payload, err := VerifyAccessToken(token, "key", verifyOpt)
+
+		assert.Nil(err)
+		assert.Equal(AccessTokenType, header["typ"])
+		assert.Equal("alice", payload["sub"])
+		assert.Equal("client", payload["client_id"])
+		assert.Equal("read write", payload["scope"])
+		assert.Equal(float64(1000), payload["auth_time"])
+		assert.Equal("acme", payload["tenant"])
+		assert.NotEmpty(payload["jti"])
+		assert.Equal(map[string]interface{}{"jkt": opt.JKT}, payload["cnf"])
+		assert.InDelta(float64(time.Now().Add(time.Hour).Unix()), payload["exp"], 5)
+	})
+
+	t.Run("Should return ErrInvalidAccessToken when options are missing", func(t *testing.T) {
+		_, err := SignAccessToken(nil, "key", &AccessTokenOption{Issuer: "iss"})
+
+		assert.True(errors.Is(err, ErrInvalidAccessToken))
+
+		_, _, err = VerifyAccessToken(nil, "key", &VerifyOption{Issuer: "iss"})
+
+		assert.True(errors.Is(err, ErrInvalidAccessToken))
+	})
+
+	t.Run("Should return ErrTokenExpired when token expired", func(t *testing.T) {
+		token, err := SignAccessToken(nil, "key", &AccessTokenOption{
+			Issuer:    opt.Issuer,
+			Audience:  opt.Audience,
+			Subject:   opt.Subject,
+			ClientID:  opt.ClientID,
+			ExpiresIn: time.Minute,
+			Clock:     func() time.Time { return time.Now().Add(-2 * time.Minute) },
+		})
+
+		assert.Nil(err)
+
+		_, _, err = VerifyAccessToken(token, "key", verifyOpt)
+
+		assert.Equal(ErrTokenExpired, err)
+
+		o := *verifyOpt
+		o.ClockTolerance = 2 * time.Minute
+
+		_, _, err = VerifyAccessToken(token, "key", &o)
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{Types: []string{AccessTokenType}, AbsoluteExpiration: true})
+
+		assert.Equal(ErrTokenExpired, err)
+
+		o.ClockTolerance, o.IngoreExpiration = 0, true
+
+		_, _, err = VerifyAccessToken(token, "key", &o)
+
+		assert.Equal(ErrTokenExpired, err)
+	})
+
+	t.Run("Should verify access token with AbsoluteExpiration", func(t *testing.T) {
+		token, err := SignAccessToken(nil, "key", opt)
+
+		assert.Nil(err)
+
+		_, payload, err := Verify(token, "key", &VerifyOption{Types: []string{AccessTokenType}, AbsoluteExpiration: true})
+
+		assert.Nil(err)
+		assert.Equal("alice", payload["sub"])
+	})
+
+	t.Run("Should pass ctx to validators", func(t *testing.T) {
+		token, err := SignAccessToken(nil, "key", opt)
+
+		assert.Nil(err)
+
+		ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+		o := *verifyOpt
+		o.Validators = []Validator{func(ctx context.Context, header Header, payload Payload) error {
+			assert.Equal("acme", ctx.Value(tenantKey{}))
+
+			return nil
+		}}
+
+		_, _, err = VerifyAccessTokenContext(ctx, token, "key", &o)
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should reject token violating the profile", func(t *testing.T) {
+		token, err := Sign(Payload{
+			"iss": opt.Issuer, "aud": "https://rs.example.com/", "sub": "alice", "client_id": "client",
+			"jti": "id", "exp": time.Now().Add(time.Hour).Unix(),
+		}, "key", nil)
+
+		assert.Nil(err)
+
+		_, _, err = VerifyAccessToken(token, "key", verifyOpt)
+
+		assert.Equal(ErrInvalidHeaderType, err)
+
+		token, err = Sign(Payload{
+			"iss": opt.Issuer, "aud": "https://rs.example.com/", "sub": "alice",
+			"jti": "id", "exp": time.Now().Add(time.Hour).Unix(),
+		}, "key", &SignOption{Type: "application/at+jwt"})
+
+		assert.Nil(err)
+
+		_, _, err = VerifyAccessToken(token, "key", verifyOpt)
+
+		var claimErr *ClaimError
+
+		assert.True(errors.As(err, &claimErr))
+		assert.Equal("client_id", claimErr.Claim)
+	})
+
+	t.Run("Should return ErrInvalidReservedClaim when audience does not match", func(t *testing.T) {
+		token, err := SignAccessToken(nil, "key", opt)
+
+		assert.Nil(err)
+
+		o := *verifyOpt
+		o.Audience = "https://unknown.example.com/"
+
+		_, _, err = VerifyAccessToken(token, "key", &o)
+
+		assert.Equal(ErrInvalidReservedClaim, err)
+	})
+}
@@ -0,0 +1,280 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+)
+
+// ClaimType represents the expected JSON type of a claim.
+type ClaimType string
+
+const (
+	// ClaimString represents a JSON string.
+	ClaimString ClaimType = "string"
+	// ClaimNumber represents a JSON number.
+	ClaimNumber ClaimType = "number"
+	// ClaimBoolean represents a JSON boolean.
+	ClaimBoolean ClaimType = "boolean"
+	// ClaimArray represents a JSON array.
+	ClaimArray ClaimType = "array"
+	// ClaimObject represents a JSON object.
+	ClaimObject ClaimType = "object"
+)
+
+// ClaimRange represents the inclusive range of a numeric claim.
+type ClaimRange struct {
+	Min float64
+	Max float64
+}
+
+// ClaimRule represents the requirements of a claim checked by Verify. The
+// value checks only apply when the claim is present, and apply to each
+// element when the claim is an array.
+type ClaimRule struct {
+	// Name is the name of the claim.
+	Name string
+	// Required specifies whether the claim must be present.
+	Required bool
+	// Type specifies the JSON type of the claim if it is not empty.
+	Type ClaimType
+	// OneOf specifies the allowed values of the claim if it is not empty.
+	OneOf []interface{}
+	// Pattern specifies the expression which string values of the claim must
+	// match if it is not nil.
+	Pattern *regexp.Regexp
+	// Range specifies the range of numeric values of the claim if it is not
+	// nil.
+	Range *ClaimRange
+}
+
+// ClaimError is returned by Verify when a claim does not meet its ClaimRule,
+// it matches ErrInvalidClaim with errors.Is.
+type ClaimError struct {
+	// Claim is the name of the offending claim.
+	Claim string
+	// Reason describes the unmet requirement.
+	Reason string
+}
+
+func (e *ClaimError) Error() string {
+	return fmt.Sprintf("%s: %s %s", ErrInvalidClaim, e.Claim, e.Reason)
+}
+
+// Unwrap returns ErrInvalidClaim.
+func (e *ClaimError) Unwrap() error {
+	return ErrInvalidClaim
+}
+
+// checkClaims returns a *ClaimError for the first claim which does not meet
+// its rule.
+func (p Payload) checkClaims(rules []ClaimRule, requireExp bool) error {
+	if _, ok := p["exp"]; !ok && requireExp {
+		return &ClaimError{Claim: "exp", Reason: "is required"}
+	}
+
+	for _, rule := range rules {
+		v, ok := p[rule.Name]
+
+		if !ok {
+			if rule.Required {
+				return &ClaimError{Claim: rule.Name, Reason: "is required"}
+			}
+
+			continue
+		}
+
+		if rule.Type != "" && claimType(v) != rule.Type {
+			return &ClaimError{Claim: rule.Name, Reason: fmt.Sprintf("should be %s", rule.Type)}
+		}
+
+		values := []interface{}{v}
+
+		if a, ok := v.([]interface{}); ok {
+			values = a
+		}
+
+		for _, v := range values {
+			if reason := rule.check(v); reason != "" {
+				return &ClaimError{Claim: rule.Name, Reason: reason}
+			}
+		}
+	}
+
+	return nil
+}
+
+// check returns the reason why v does not meet the rule, or an empty string.
+func (rule ClaimRule) check(v interface{}) string {
+	if len(rule.OneOf) > 0 {
+		allowed := false
+
+		for _, o := range rule.OneOf {
+			if claimEqual(v, o) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return fmt.Sprintf("has disallowed value %v", v)
+		}
+	}
+
+	if rule.Pattern != nil {
+		if s, ok := v.(string); !ok || !rule.Pattern.MatchString(s) {
+			return fmt.Sprintf("should match %s", rule.Pattern)
+		}
+	}
+
+	if rule.Range != nil {
+		min, ok := compareNumbers(v, rule.Range.Min)
+		max, _ := compareNumbers(v, rule.Range.Max)
+
+		if !ok || min < 0 || max > 0 {
+			return fmt.Sprintf("should be between %v and %v", rule.Range.Min, rule.Range.Max)
+		}
+	}
+
+	return ""
+}
+
+func claimType(v interface{}) ClaimType {
+	switch v.(type) {
+	case string:
+		return ClaimString
+	case bool:
+		return ClaimBoolean
+	case []interface{}:
+		return ClaimArray
+	case map[string]interface{}:
+		return ClaimObject
+	}
+
+	if _, ok := claimNumber(v); ok {
+		return ClaimNumber
+	}
+
+	return ""
+}
+
+// claimEqual compares a decoded claim value with an expected one, numbers of
+// any Go type are compared exactly by value.
+func claimEqual(v, expected interface{}) bool {
+	if _, ok := claimRat(v); ok {
+		c, ok := compareNumbers(v, expected)
+
+		return ok && c == 0
+	}
+
+	switch v.(type) {
+	case string, bool:
+		return v == expected
+	}
+
+	return false
+}
+
+// compareNumbers compares the numbers a and b exactly, unless one of them is
+// a float and the other is not an integer, in which case they are compared as
+// float64 so that a decoded 0.1 equals the float64 0.1.
+func compareNumbers(a, b interface{}) (int, bool) {
+	ra, ok := claimRat(a)
+
+	if !ok {
+		return 0, false
+	}
+
+	rb, ok := claimRat(b)
+
+	if !ok {
+		return 0, false
+	}
+
+	if (isFloat(a) && !rb.IsInt()) || (isFloat(b) && !ra.IsInt()) {
+		fa, _ := ra.Float64()
+		fb, _ := rb.Float64()
+
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+
+		return 0, true
+	}
+
+	return ra.Cmp(rb), true
+}
+
+func isFloat(v interface{}) bool {
+	switch v.(type) {
+	case float32, float64:
+		return true
+	}
+
+	return false
+}
+
+// claimRat returns the exact value of the numeric claim v, so that int64 and
+// json.Number decoded strictly are compared without losing precision.
+func claimRat(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(string(n))
+	case float64:
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, false
+		}
+
+		return new(big.Rat).SetFloat64(n), true
+	case float32:
+		return claimRat(float64(n))
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint64:
+		return new(big.Rat).SetUint64(n), true
+	}
+
+	return nil, false
+}
+
+// claimNumber returns the value of the numeric claim v, which is float64 when
+// decoded by default, and int64 or json.Number when decoded strictly.
+func claimNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+
+	return 0, false
+}
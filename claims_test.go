@@ -0,0 +1,99 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimRules(t *testing.T) {
+	assert := assert.New(t)
+
+	token, err := Sign(Payload{
+		"role":   "admin",
+		"scopes": []string{"read", "write"},
+		"level":  3,
+		"email":  "foo@example.com",
+	}, "key", &SignOption{ExpiresIn: time.Minute})
+
+	assert.Nil(err)
+
+	verifyClaims := func(rules ...ClaimRule) error {
+		_, _, err := Verify(token, "key", &VerifyOption{Claims: rules})
+
+		return err
+	}
+
+	t.Run("Should pass when claims meet the rules", func(t *testing.T) {
+		assert.Nil(verifyClaims(
+			ClaimRule{Name: "role", Required: true, Type: ClaimString, OneOf: []interface{}{"admin", "user"}},
+			ClaimRule{Name: "scopes", Type: ClaimArray, OneOf: []interface{}{"read", "write", "delete"}},
+			ClaimRule{Name: "level", Type: ClaimNumber, OneOf: []interface{}{1, 3}, Range: &ClaimRange{Min: 1, Max: 5}},
+			ClaimRule{Name: "email", Pattern: regexp.MustCompile(`@example\.com$`)},
+			ClaimRule{Name: "missing", Type: ClaimBoolean},
+		))
+	})
+
+	t.Run("Should return ClaimError naming the offending claim", func(t *testing.T) {
+		for claim, rule := range map[string]ClaimRule{
+			"missing": {Name: "missing", Required: true},
+			"role":    {Name: "role", Type: ClaimObject},
+			"scopes":  {Name: "scopes", OneOf: []interface{}{"read"}},
+			"level":   {Name: "level", Range: &ClaimRange{Min: 4, Max: 5}},
+			"email":   {Name: "email", Pattern: regexp.MustCompile(`@example\.org$`)},
+		} {
+			err := verifyClaims(rule)
+
+			var claimErr *ClaimError
+
+			assert.True(errors.Is(err, ErrInvalidClaim))
+			assert.True(errors.As(err, &claimErr))
+			assert.Equal(claim, claimErr.Claim)
+		}
+	})
+
+	t.Run("Should return ClaimError when exp is required but missing", func(t *testing.T) {
+		token, err := Sign(Payload{"foo": "bar"}, "key", nil)
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{IngoreExpiration: true, RequireExpiration: true})
+
+		var claimErr *ClaimError
+
+		assert.True(errors.As(err, &claimErr))
+		assert.Equal("exp", claimErr.Claim)
+		assert.Equal("jwt: invalid claim: exp is required", err.Error())
+
+		_, _, err = Verify(token, "key", &VerifyOption{IngoreExpiration: true})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should compare strictly decoded numbers exactly", func(t *testing.T) {
+		token := rawURLToken(t, Header{"alg": "HS256", "typ": "JWT"}, Payload{
+			"iat":   time.Now().Unix(),
+			"exp":   60,
+			"id":    int64(9007199254740993),
+			"ratio": json.Number("0.1"),
+		}, "key")
+
+		verifyStrict := func(rules ...ClaimRule) error {
+			_, _, err := Verify(token, "key", &VerifyOption{Strict: &StrictOption{}, Claims: rules})
+
+			return err
+		}
+
+		assert.True(errors.Is(verifyStrict(ClaimRule{Name: "id", OneOf: []interface{}{int64(9007199254740992)}}), ErrInvalidClaim))
+		assert.True(errors.Is(verifyStrict(ClaimRule{Name: "id", OneOf: []interface{}{float64(9007199254740992)}}), ErrInvalidClaim))
+		assert.True(errors.Is(verifyStrict(ClaimRule{Name: "id", Range: &ClaimRange{Min: 0, Max: 9007199254740992}}), ErrInvalidClaim))
+		assert.Nil(verifyStrict(ClaimRule{Name: "id", OneOf: []interface{}{int64(9007199254740993)}}))
+		assert.Nil(verifyStrict(ClaimRule{Name: "id", OneOf: []interface{}{json.Number("9007199254740993")}}))
+		assert.Nil(verifyStrict(ClaimRule{Name: "id", OneOf: []interface{}{uint64(9007199254740993)}}))
+		assert.Nil(verifyStrict(ClaimRule{Name: "ratio", OneOf: []interface{}{0.1}, Range: &ClaimRange{Min: 0.1, Max: 0.1}}))
+	})
+}
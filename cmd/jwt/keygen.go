@@ -0,0 +1,153 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/DavidCai1993/jwt"
+)
+
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+func keygen(args []string, stdin io.Reader, stdout io.Writer) error {
+	var (
+		fs     = flag.NewFlagSet("jwt keygen", flag.ContinueOnError)
+		typ    = fs.String("type", "hmac", "key type: hmac, rsa, ec or ed25519")
+		bits   = fs.Int("bits", 0, "size of the HMAC secret (default 256) or RSA key (default 2048) in bits")
+		curve  = fs.String("curve", "P-256", "curve of the EC key: P-256, P-384 or P-521")
+		format = fs.String("format", "pem", "output format: pem or jwk")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *bits < 0 {
+		return fmt.Errorf("invalid bits %d", *bits)
+	}
+
+	if *format != "pem" && *format != "jwk" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+
+	switch *typ {
+	case "hmac":
+		if *bits == 0 {
+			*bits = 256
+		}
+
+		if *bits%8 != 0 {
+			return fmt.Errorf("bits of the HMAC secret must be a multiple of 8, got %d", *bits)
+		}
+
+		secret := make([]byte, *bits/8)
+
+		if _, err = rand.Read(secret); err != nil {
+			return err
+		}
+
+		if *format == "pem" {
+			_, err = fmt.Fprintln(stdout, base64.RawURLEncoding.EncodeToString(secret))
+			return err
+		}
+
+		key = secret
+	case "rsa":
+		if *bits == 0 {
+			*bits = 2048
+		}
+
+		key, err = rsa.GenerateKey(rand.Reader, *bits)
+	case "ec":
+		c, ok := curves[*curve]
+
+		if !ok {
+			return fmt.Errorf("unknown curve %q", *curve)
+		}
+
+		key, err = ecdsa.GenerateKey(c, rand.Reader)
+	case "ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return fmt.Errorf("unknown key type %q", *typ)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if *format == "jwk" {
+		return writeJWK(stdout, key)
+	}
+
+	return writePEM(stdout, key)
+}
+
+func writePEM(w io.Writer, key interface{}) error {
+	private, err := x509.MarshalPKCS8PrivateKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	public, err := x509.MarshalPKIXPublicKey(key.(crypto.Signer).Public())
+
+	if err != nil {
+		return err
+	}
+
+	if err = pem.Encode(w, &pem.Block{Type: "PRIVATE KEY", Bytes: private}); err != nil {
+		return err
+	}
+
+	return pem.Encode(w, &pem.Block{Type: "PUBLIC KEY", Bytes: public})
+}
+
+func writeJWK(w io.Writer, key interface{}) error {
+	jwk, err := jwt.NewJWK(key)
+
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(jwk, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	if jwk["kty"] == "oct" {
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	public, err := json.MarshalIndent(jwk.Public(), "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n%s\n", b, public)
+
+	return err
+}
@@ -0,0 +1,403 @@
+// Command jwt signs, verifies and decodes JSON web tokens, and generates keys
+// for them.
+//
+// Usage:
+//
+//	jwt sign -key secret.txt -claims '{"foo":"bar"}' -exp 1h
+//	jwt verify -key secret.txt <token>
+//	jwt decode <token>
+//	jwt lint -key secret.txt <token>
+//	jwt keygen -type rsa -bits 2048
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DavidCai1993/jwt"
+)
+
+const usage = `usage: jwt <command> [flags]
+
+commands:
+  sign     sign claims into a token
+  verify   verify a token and print its header and payload
+  decode   print the header and payload of a token without verifying it
+  keygen   generate an HMAC secret or an RSA, EC or Ed25519 key pair
+  lint     report the practices weakening the security of a token
+
+Run "jwt <command> -h" for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	commands := map[string]func([]string, io.Reader, io.Writer) error{
+		"sign":   sign,
+		"verify": verify,
+		"decode": decode,
+		"keygen": keygen,
+		"lint":   lint,
+	}
+
+	command, ok := commands[args[0]]
+
+	if !ok {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	if err := command(args[1:], stdin, stdout); err != nil {
+		if err == flag.ErrHelp {
+			return 2
+		}
+
+		fmt.Fprintf(stderr, "jwt %s: %v\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
+
+// claimFlags collects the repeated -claim key=value flags.
+type claimFlags jwt.Payload
+
+func (cf claimFlags) String() string {
+	return ""
+}
+
+func (cf claimFlags) Set(s string) error {
+	i := strings.IndexByte(s, '=')
+
+	if i <= 0 {
+		return errors.New("claim should be in the form of key=value")
+	}
+
+	var v interface{}
+
+	if err := json.Unmarshal([]byte(s[i+1:]), &v); err != nil {
+		v = s[i+1:]
+	}
+
+	cf[s[:i]] = v
+
+	return nil
+}
+
+func sign(args []string, stdin io.Reader, stdout io.Writer) error {
+	var (
+		fs     = flag.NewFlagSet("jwt sign", flag.ContinueOnError)
+		alg    = fs.String("alg", string(jwt.HS256), "signing algorithm")
+		key    = fs.String("key", "", "file of the HMAC secret or PEM private key")
+		claims = fs.String("claims", "{}", `claims in JSON, "-" to read from stdin or "@file" to read from file`)
+		header = fs.String("header", "", "custom header in JSON")
+		exp    = fs.Duration("exp", 0, "lifetime of the token")
+		iss    = fs.String("iss", "", "issuer")
+		aud    = fs.String("aud", "", "audience")
+		sub    = fs.String("sub", "", "subject")
+		typ    = fs.String("typ", "JWT", "type of the token")
+		extra  = claimFlags{}
+	)
+
+	fs.Var(extra, "claim", "claim in the form of key=value, value is parsed as JSON if possible (repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	k, err := loadKey(*key, jwt.Algorithm(*alg), true)
+
+	if err != nil {
+		return err
+	}
+
+	b, err := readArg(*claims, stdin)
+
+	if err != nil {
+		return err
+	}
+
+	payload := jwt.Payload{}
+
+	if err = json.Unmarshal(b, &payload); err != nil {
+		return fmt.Errorf("invalid claims: %v", err)
+	}
+
+	for name, v := range extra {
+		payload[name] = v
+	}
+
+	opt := &jwt.SignOption{
+		Algorithm: jwt.Algorithm(*alg),
+		ExpiresIn: *exp,
+		Issuer:    *iss,
+		Audience:  *aud,
+		Subject:   *sub,
+		Type:      *typ,
+	}
+
+	if *header != "" {
+		if err = json.Unmarshal([]byte(*header), &opt.Header); err != nil {
+			return fmt.Errorf("invalid header: %v", err)
+		}
+	}
+
+	token, err := jwt.Sign(payload, k, opt)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(stdout, "%s\n", token)
+
+	return err
+}
+
+func verify(args []string, stdin io.Reader, stdout io.Writer) error {
+	var (
+		fs        = flag.NewFlagSet("jwt verify", flag.ContinueOnError)
+		alg       = fs.String("alg", string(jwt.HS256), "signing algorithm")
+		key       = fs.String("key", "", "file of the HMAC secret, or PEM public key, certificate or private key")
+		iss       = fs.String("iss", "", "expected issuer")
+		aud       = fs.String("aud", "", "expected audience")
+		sub       = fs.String("sub", "", "expected subject")
+		ignoreExp = fs.Bool("ignore-exp", false, "do not validate the expiration")
+		absExp    = fs.Bool("absolute-exp", false, "validate exp as an absolute time instead of a lifetime after iat")
+		tolerance = fs.Duration("tolerance", 0, "clock tolerance when validating the expiration")
+		typ       = fs.String("typ", "JWT", "comma-separated accepted types of the token")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	k, err := loadKey(*key, jwt.Algorithm(*alg), false)
+
+	if err != nil {
+		return err
+	}
+
+	token, err := readToken(fs.Args(), stdin)
+
+	if err != nil {
+		return err
+	}
+
+	header, payload, err := jwt.Verify(token, k, &jwt.VerifyOption{
+		Algorithm:          jwt.Algorithm(*alg),
+		Issuer:             *iss,
+		Audience:           *aud,
+		Subject:            *sub,
+		IngoreExpiration:   *ignoreExp,
+		AbsoluteExpiration: *absExp,
+		ClockTolerance:     *tolerance,
+		Types:              strings.Split(*typ, ","),
+	})
+
+	if err != nil {
+		return fmt.Errorf("invalid token: %v", err)
+	}
+
+	fmt.Fprintln(stdout, "Valid token")
+
+	return printToken(stdout, header, payload, *absExp)
+}
+
+func decode(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("jwt decode", flag.ContinueOnError)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	token, err := readToken(fs.Args(), stdin)
+
+	if err != nil {
+		return err
+	}
+
+	header, payload, err := jwt.Decode(token)
+
+	if err != nil {
+		return fmt.Errorf("invalid token: %v", err)
+	}
+
+	return printToken(stdout, header, payload, false)
+}
+
+func lint(args []string, stdin io.Reader, stdout io.Writer) error {
+	var (
+		fs          = flag.NewFlagSet("jwt lint", flag.ContinueOnError)
+		alg         = fs.String("alg", string(jwt.HS256), "signing algorithm, used to load the key")
+		key         = fs.String("key", "", "optional file of the HMAC secret or PEM key")
+		maxLifetime = fs.Duration("max-lifetime", 0, "longest acceptable lifetime (default 24h)")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		k   interface{}
+		err error
+	)
+
+	if *key != "" {
+		if k, err = loadKey(*key, jwt.Algorithm(*alg), false); err != nil {
+			return err
+		}
+	}
+
+	token, err := readToken(fs.Args(), stdin)
+
+	if err != nil {
+		return err
+	}
+
+	errs := 0
+
+	for _, f := range jwt.Lint(token, k, &jwt.LintOption{MaxLifetime: *maxLifetime}) {
+		fmt.Fprintln(stdout, f)
+
+		if f.Severity == jwt.SeverityError {
+			errs++
+		}
+	}
+
+	if errs > 0 {
+		return fmt.Errorf("%d error(s) found", errs)
+	}
+
+	return nil
+}
+
+// printToken prints the header and payload, and the times of the token. "exp"
+// is an absolute time if absolute is true or the token looks like using it.
+func printToken(w io.Writer, header jwt.Header, payload jwt.Payload, absolute bool) error {
+	for _, part := range []struct {
+		name  string
+		value interface{}
+	}{{"Header", header}, {"Payload", payload}} {
+		b, err := json.MarshalIndent(part.value, "", "  ")
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s:\n%s\n", part.name, b)
+	}
+
+	iat, hasIat := payload["iat"].(float64)
+
+	if hasIat {
+		fmt.Fprintf(w, "Issued at:  %s\n", formatUnix(iat))
+	}
+
+	if nbf, ok := payload["nbf"].(float64); ok {
+		fmt.Fprintf(w, "Not before: %s\n", formatUnix(nbf))
+	}
+
+	if exp, ok := payload["exp"].(float64); ok {
+		// "exp" is the lifetime of the token in seconds counted from "iat",
+		// unless it is an absolute time as in access tokens, which is also
+		// the case when it is later than "iat".
+		absolute = absolute || isAccessToken(header) || (hasIat && exp > iat)
+		expiresAt := time.Unix(int64(exp), 0)
+
+		if !absolute {
+			if !hasIat {
+				return nil
+			}
+
+			expiresAt = time.Unix(int64(iat+exp), 0)
+		}
+
+		state := "valid"
+
+		if !time.Now().Before(expiresAt) {
+			state = "expired"
+		}
+
+		fmt.Fprintf(w, "Expires at: %s (%s)\n", expiresAt.UTC().Format(time.RFC3339), state)
+	}
+
+	return nil
+}
+
+// isAccessToken reports whether the "typ" header is the one of RFC 9068 access
+// tokens, whose "exp" is an absolute time.
+func isAccessToken(header jwt.Header) bool {
+	typ, _ := header["typ"].(string)
+
+	return strings.TrimPrefix(strings.ToLower(typ), "application/") == jwt.AccessTokenType
+}
+
+func formatUnix(sec float64) string {
+	return time.Unix(int64(sec), 0).UTC().Format(time.RFC3339)
+}
+
+// readArg returns s itself, the content of stdin if s is "-", or the content
+// of the file if s starts with "@".
+func readArg(s string, stdin io.Reader) ([]byte, error) {
+	switch {
+	case s == "-":
+		return ioutil.ReadAll(stdin)
+	case strings.HasPrefix(s, "@"):
+		return ioutil.ReadFile(s[1:])
+	default:
+		return []byte(s), nil
+	}
+}
+
+func readToken(args []string, stdin io.Reader) ([]byte, error) {
+	if len(args) > 1 {
+		return nil, errors.New("too many arguments")
+	}
+
+	arg := "-"
+
+	if len(args) == 1 {
+		arg = args[0]
+	}
+
+	token, err := readArg(arg, stdin)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(token), nil
+}
+
+func loadKey(path string, alg jwt.Algorithm, signing bool) (interface{}, error) {
+	if path == "" {
+		return nil, errors.New("-key is required")
+	}
+
+	b, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if signing {
+		return jwt.LoadSigningKey(alg, bytes.TrimSpace(b))
+	}
+
+	return jwt.LoadVerifyingKey(alg, bytes.TrimSpace(b))
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DavidCai1993/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "jwt")
+
+	assert.Nil(err)
+
+	defer os.RemoveAll(dir)
+
+	secretFile := filepath.Join(dir, "secret")
+	assert.Nil(ioutil.WriteFile(secretFile, []byte("key\n"), 0600))
+
+	t.Run("Should print usage when command is unknown", func(t *testing.T) {
+		code, _, stderr := runCommand("", "unknown")
+
+		assert.Equal(2, code)
+		assert.Contains(stderr, "usage: jwt")
+	})
+
+	t.Run("Should sign, verify and decode with HMAC", func(t *testing.T) {
+		code, token, _ := runCommand(`{"foo":"bar"}`, "sign", "-key", secretFile, "-claims", "-",
+			"-claim", "n=1", "-claim", "s=text", "-iss", "testIssuer", "-exp", "1h")
+
+		assert.Equal(0, code)
+
+		_, payload, err := jwt.Verify([]byte(strings.TrimSpace(token)), "key", nil)
+
+		assert.Nil(err)
+		assert.Equal("bar", payload["foo"])
+		assert.Equal(float64(1), payload["n"])
+		assert.Equal("text", payload["s"])
+
+		code, stdout, _ := runCommand(token, "verify", "-key", secretFile, "-iss", "testIssuer")
+
+		assert.Equal(0, code)
+		assert.Contains(stdout, "Valid token")
+		assert.Contains(stdout, `"foo": "bar"`)
+		assert.Contains(stdout, "Issued at:")
+		assert.Contains(stdout, "(valid)")
+
+		code, stdout, _ = runCommand("", "decode", strings.TrimSpace(token))
+
+		assert.Equal(0, code)
+		assert.Contains(stdout, `"typ": "JWT"`)
+		assert.Contains(stdout, "Expires at:")
+	})
+
+	t.Run("Should print failure reason when verification failed", func(t *testing.T) {
+		_, token, _ := runCommand("", "sign", "-key", secretFile, "-iss", "testIssuer")
+
+		code, _, stderr := runCommand(token, "verify", "-key", secretFile, "-iss", "otherIssuer", "-ignore-exp")
+
+		assert.Equal(1, code)
+		assert.Contains(stderr, jwt.ErrInvalidReservedClaim.Error())
+
+		code, _, stderr = runCommand("", "decode", "a.b")
+
+		assert.Equal(1, code)
+		assert.Contains(stderr, jwt.ErrInvalidToken.Error())
+	})
+
+	t.Run("Should sign and verify explicitly typed token", func(t *testing.T) {
+		_, token, _ := runCommand("", "sign", "-key", secretFile, "-typ", "at+jwt", "-exp", "1h")
+
+		code, _, stderr := runCommand(token, "verify", "-key", secretFile)
+
+		assert.Equal(1, code)
+		assert.Contains(stderr, jwt.ErrInvalidHeaderType.Error())
+
+		code, stdout, _ := runCommand(token, "verify", "-key", secretFile, "-typ", "JWT,application/at+jwt")
+
+		assert.Equal(0, code)
+		assert.Contains(stdout, `"typ": "at+jwt"`)
+	})
+
+	t.Run("Should verify and decode token with absolute exp", func(t *testing.T) {
+		token, err := jwt.SignAccessToken(nil, "key", &jwt.AccessTokenOption{
+			Issuer:    "testIssuer",
+			Audience:  []string{"testAudience"},
+			Subject:   "testSubject",
+			ClientID:  "testClient",
+			ExpiresIn: time.Hour,
+			Clock:     func() time.Time { return time.Now().Add(-2 * time.Hour) },
+		})
+
+		assert.Nil(err)
+
+		code, _, stderr := runCommand(string(token), "verify", "-key", secretFile, "-typ", "at+jwt", "-absolute-exp")
+
+		assert.Equal(1, code)
+		assert.Contains(stderr, jwt.ErrTokenExpired.Error())
+
+		code, stdout, _ := runCommand(string(token), "verify", "-key", secretFile, "-typ", "at+jwt", "-absolute-exp", "-tolerance", "2h")
+
+		assert.Equal(0, code)
+		assert.Contains(stdout, "(expired)")
+
+		code, stdout, _ = runCommand(string(token), "decode")
+
+		assert.Equal(0, code)
+		assert.Contains(stdout, "(expired)")
+	})
+
+	t.Run("Should lint token", func(t *testing.T) {
+		_, token, _ := runCommand("", "sign", "-key", secretFile)
+
+		code, stdout, stderr := runCommand(token, "lint", "-key", secretFile)
+
+		assert.Equal(1, code)
+		assert.Contains(stdout, "error: missing-exp")
+		assert.Contains(stdout, "error: weak-secret")
+		assert.Contains(stderr, "2 error(s) found")
+
+		_, token, _ = runCommand("", "sign", "-key", secretFile, "-exp", "1h")
+
+		code, stdout, _ = runCommand(token, "lint")
+
+		assert.Equal(0, code)
+		assert.Contains(stdout, "warning: missing-aud")
+	})
+
+	t.Run("Should generate RSA key usable by sign and verify", func(t *testing.T) {
+		code, stdout, _ := runCommand("", "keygen", "-type", "rsa", "-bits", "1024")
+
+		assert.Equal(0, code)
+
+		block, rest := pem.Decode([]byte(stdout))
+
+		assert.Equal("PRIVATE KEY", block.Type)
+
+		block, _ = pem.Decode(rest)
+
+		assert.Equal("PUBLIC KEY", block.Type)
+
+		keyFile := filepath.Join(dir, "rsa.pem")
+		assert.Nil(ioutil.WriteFile(keyFile, []byte(stdout), 0600))
+
+		code, token, _ := runCommand("", "sign", "-alg", "RS256", "-key", keyFile)
+
+		assert.Equal(0, code)
+
+		code, _, _ = runCommand(token, "verify", "-alg", "RS256", "-key", keyFile, "-ignore-exp")
+
+		assert.Equal(0, code)
+	})
+
+	t.Run("Should generate keys in JWK", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"-type", "ec", "-curve", "P-384"},
+			{"-type", "ed25519"},
+			{"-type", "hmac"},
+		} {
+			code, stdout, _ := runCommand("", append([]string{"keygen", "-format", "jwk"}, args...)...)
+
+			assert.Equal(0, code)
+
+			var jwk jwt.JWK
+
+			assert.Nil(json.NewDecoder(strings.NewReader(stdout)).Decode(&jwk))
+			assert.NotEmpty(jwk["kty"])
+		}
+
+		code, stdout, _ := runCommand("", "keygen", "-type", "ec", "-format", "pem")
+
+		assert.Equal(0, code)
+		assert.Contains(stdout, "PUBLIC KEY")
+
+		code, _, _ = runCommand("", "keygen", "-type", "dsa")
+
+		assert.Equal(1, code)
+	})
+
+	t.Run("Should return error when bits are invalid", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"-type", "hmac", "-bits", "-8"},
+			{"-type", "hmac", "-bits", "12"},
+			{"-type", "rsa", "-bits", "-1"},
+		} {
+			code, _, stderr := runCommand("", append([]string{"keygen"}, args...)...)
+
+			assert.Equal(1, code, args)
+			assert.Contains(stderr, "bits", args)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package jwt
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Codec marshals and unmarshals the JSON header and payload of tokens.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+var (
+	defaultCodecMu sync.RWMutex
+	defaultCodec   Codec = stdCodec{}
+)
+
+// SetDefaultCodec sets the Codec used when SignOption or VerifyOption does
+// not specify one, encoding/json will be used if codec is nil.
+func SetDefaultCodec(codec Codec) {
+	defaultCodecMu.Lock()
+	defer defaultCodecMu.Unlock()
+
+	if codec == nil {
+		codec = stdCodec{}
+	}
+
+	defaultCodec = codec
+}
+
+// jsonCodec returns codec, or the default Codec if it is nil.
+func jsonCodec(codec Codec) Codec {
+	if codec != nil {
+		return codec
+	}
+
+	defaultCodecMu.RLock()
+	defer defaultCodecMu.RUnlock()
+
+	return defaultCodec
+}
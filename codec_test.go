@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// numberCodec decodes numbers as json.Number and counts its calls.
+type numberCodec struct {
+	marshaled   int
+	unmarshaled int
+}
+
+func (c *numberCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshaled++
+
+	return json.Marshal(v)
+}
+
+func (c *numberCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshaled++
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode(v)
+}
+
+func TestCodec(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Should use the Codec of options", func(t *testing.T) {
+		codec := &numberCodec{}
+
+		token, err := Sign(Payload{"id": 42}, "key", &SignOption{ExpiresIn: time.Minute, Codec: codec})
+
+		assert.Nil(err)
+		assert.Equal(2, codec.marshaled)
+
+		_, payload, err := Verify(token, "key", &VerifyOption{Codec: codec})
+
+		assert.Nil(err)
+		assert.Equal(2, codec.unmarshaled)
+		assert.Equal(json.Number("42"), payload["id"])
+	})
+
+	t.Run("Should use the default Codec", func(t *testing.T) {
+		codec := &numberCodec{}
+
+		SetDefaultCodec(codec)
+		defer SetDefaultCodec(nil)
+
+		token, err := Sign(Payload{"id": 42}, "key", &SignOption{ExpiresIn: time.Minute})
+
+		assert.Nil(err)
+
+		_, payload, err := Verify(token, "key", nil)
+
+		assert.Nil(err)
+		assert.Equal(json.Number("42"), payload["id"])
+
+		_, err = Refresh(token, "key", nil)
+
+		assert.Nil(err)
+		assert.Equal(4, codec.marshaled)
+		assert.Equal(4, codec.unmarshaled)
+	})
+
+	t.Run("Should restore encoding/json when nil is set", func(t *testing.T) {
+		token, err := Sign(Payload{"id": 42}, "key", nil)
+
+		assert.Nil(err)
+
+		_, payload, err := Decode(token)
+
+		assert.Nil(err)
+		assert.Equal(float64(42), payload["id"])
+	})
+}
@@ -0,0 +1,83 @@
+package jwt
+
+import "fmt"
+
+// CriticalHeaderHandler validates the header parameter it is specified for in
+// VerifyOption.CriticalHeaders when the parameter is listed in the "crit"
+// header of a verified token. The payload is nil for tokens verified by
+// VerifyDetached.
+type CriticalHeaderHandler func(header Header, payload Payload) error
+
+// registeredHeaders are the header parameters defined by RFC 7515, which must
+// not be listed in "crit".
+var registeredHeaders = map[string]bool{
+	"alg": true, "jku": true, "jwk": true, "kid": true, "x5u": true, "x5c": true,
+	"x5t": true, "x5t#S256": true, "typ": true, "cty": true, "crit": true,
+}
+
+// checkCritical validates the "crit" header, and then runs the handlers of the
+// parameters it lists. "b64" is understood natively, but can only be false
+// for detached content. Parameters without handlers are rejected as RFC 7515
+// section 4.1.11 requires.
+func (h Header) checkCritical(payload Payload, detached bool, handlers map[string]CriticalHeaderHandler) error {
+	v, ok := h["crit"]
+
+	if !ok {
+		return nil
+	}
+
+	names, ok := v.([]interface{})
+
+	if !ok || len(names) == 0 {
+		return fmt.Errorf("%w: crit should be a non-empty array", ErrInvalidCriticalHeader)
+	}
+
+	seen := map[string]bool{}
+	toRun := make([]CriticalHeaderHandler, 0, len(names))
+
+	for _, v := range names {
+		name, ok := v.(string)
+
+		if !ok || name == "" {
+			return fmt.Errorf("%w: crit should contain names", ErrInvalidCriticalHeader)
+		}
+
+		if seen[name] {
+			return fmt.Errorf("%w: %s is listed more than once", ErrInvalidCriticalHeader, name)
+		}
+
+		seen[name] = true
+
+		if registeredHeaders[name] {
+			return fmt.Errorf("%w: %s is a registered header", ErrInvalidCriticalHeader, name)
+		}
+
+		if _, ok = h[name]; !ok {
+			return fmt.Errorf("%w: %s is missing", ErrInvalidCriticalHeader, name)
+		}
+
+		if name == "b64" {
+			if b64, ok := h["b64"].(bool); !ok || (!b64 && !detached) {
+				return fmt.Errorf("%w: unencoded payload is only supported for detached content", ErrInvalidCriticalHeader)
+			}
+
+			continue
+		}
+
+		handler, ok := handlers[name]
+
+		if !ok || handler == nil {
+			return fmt.Errorf("%w: %s is not understood", ErrInvalidCriticalHeader, name)
+		}
+
+		toRun = append(toRun, handler)
+	}
+
+	for _, handler := range toRun {
+		if err := handler(h, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
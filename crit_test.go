@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCriticalHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	sign := func(header Header) []byte {
+		token, err := Sign(Payload{"foo": "bar"}, "key", &SignOption{ExpiresIn: time.Minute, Header: header})
+
+		assert.Nil(err)
+
+		return token
+	}
+
+	t.Run("Should return ErrInvalidCriticalHeader when header is not understood", func(t *testing.T) {
+		_, _, err := Verify(sign(Header{"exp-ext": 1, "crit": []string{"exp-ext"}}), "key", nil)
+
+		assert.True(errors.Is(err, ErrInvalidCriticalHeader))
+	})
+
+	t.Run("Should return ErrInvalidCriticalHeader when crit is malformed", func(t *testing.T) {
+		for _, header := range []Header{
+			{"crit": "exp-ext"},
+			{"crit": []string{}},
+			{"crit": []interface{}{1}},
+			{"crit": []string{"exp-ext"}},
+			{"crit": []string{"kid"}, "kid": "testKid"},
+			{"crit": []string{"b64"}, "b64": false},
+		} {
+			_, _, err := Verify(sign(header), "key", nil)
+
+			assert.True(errors.Is(err, ErrInvalidCriticalHeader), header)
+		}
+	})
+
+	t.Run("Should run handler of the option", func(t *testing.T) {
+		errExpired := errors.New("extension expired")
+
+		opt := &VerifyOption{CriticalHeaders: map[string]CriticalHeaderHandler{
+			"exp-ext": func(header Header, payload Payload) error {
+				if header["exp-ext"] != float64(1) || payload["foo"] != "bar" {
+					return errExpired
+				}
+
+				return nil
+			},
+		}}
+
+		_, _, err := Verify(sign(Header{"exp-ext": 1, "crit": []string{"exp-ext"}}), "key", opt)
+
+		assert.Nil(err)
+
+		_, _, err = Verify(sign(Header{"exp-ext": 1, "crit": []string{"exp-ext"}}), "key", nil)
+
+		assert.True(errors.Is(err, ErrInvalidCriticalHeader))
+
+		_, _, err = Verify(sign(Header{"exp-ext": 2, "crit": []string{"exp-ext"}}), "key", opt)
+
+		assert.Equal(errExpired, err)
+
+		_, _, err = Verify(sign(Header{"exp-ext": 2, "crit": []string{"exp-ext", "exp-ext"}}), "key", opt)
+
+		assert.True(errors.Is(err, ErrInvalidCriticalHeader))
+	})
+
+	t.Run("Should return ErrInvalidCriticalHeader when detached header is not understood", func(t *testing.T) {
+		token, err := SignDetached([]byte("content"), "key", &SignOption{
+			Header: Header{"exp-ext": 1, "crit": []string{"exp-ext"}},
+		})
+
+		assert.Nil(err)
+
+		_, err = VerifyDetached(token, []byte("content"), "key", nil)
+
+		assert.True(errors.Is(err, ErrInvalidCriticalHeader))
+	})
+}
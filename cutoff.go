@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"time"
+)
+
+// SubjectCutoff represents a store of per-subject cutoffs consulted by Verify,
+// the tokens of a subject issued before its cutoff are rejected. It is useful
+// for invalidating all the tokens of a subject at once, like when logging out
+// of all devices or changing password.
+type SubjectCutoff interface {
+	// RevokeBefore sets the cutoff of subject to t.
+	RevokeBefore(subject string, t time.Time) error
+	// Cutoff returns the cutoff of subject, it is zero if there is none.
+	Cutoff(subject string) (time.Time, error)
+}
+
+// checkSubjectCutoff compares "iat" with the cutoff at the one second
+// resolution of "iat", so a token issued in the same second as the cutoff is
+// still valid.
+func (p Payload) checkSubjectCutoff(sc SubjectCutoff) error {
+	sub, ok := p["sub"].(string)
+
+	if !ok || sub == "" {
+		return nil
+	}
+
+	cutoff, err := sc.Cutoff(sub)
+
+	if err != nil || cutoff.IsZero() {
+		return err
+	}
+
+	iat, err := p.iat()
+
+	if err != nil {
+		return err
+	}
+
+	if iat.Before(cutoff.Truncate(time.Second)) {
+		return ErrTokenIssuedBeforeCutoff
+	}
+
+	return nil
+}
+
+// MemorySubjectCutoff is a SubjectCutoff which keeps the cutoffs in memory.
+type MemorySubjectCutoff struct {
+	store *timeStore
+}
+
+// NewMemorySubjectCutoff returns a new empty MemorySubjectCutoff.
+func NewMemorySubjectCutoff() *MemorySubjectCutoff {
+	store, _ := newTimeStore("")
+
+	return &MemorySubjectCutoff{store: store}
+}
+
+// RevokeBefore implements SubjectCutoff.
+func (ms *MemorySubjectCutoff) RevokeBefore(subject string, t time.Time) error {
+	return ms.store.set(subject, t, nil)
+}
+
+// Cutoff implements SubjectCutoff.
+func (ms *MemorySubjectCutoff) Cutoff(subject string) (time.Time, error) {
+	t, _ := ms.store.get(subject)
+
+	return t, nil
+}
+
+// FileSubjectCutoff is a SubjectCutoff which persists the cutoffs to a JSON
+// file, so that they survive restarts.
+type FileSubjectCutoff struct {
+	store *timeStore
+}
+
+// NewFileSubjectCutoff returns a FileSubjectCutoff backed by the file at
+// path, loading the cutoffs already stored in it. The file will be created on
+// the first call of RevokeBefore if it does not exist.
+func NewFileSubjectCutoff(path string) (*FileSubjectCutoff, error) {
+	store, err := newTimeStore(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSubjectCutoff{store: store}, nil
+}
+
+// RevokeBefore implements SubjectCutoff.
+func (fs *FileSubjectCutoff) RevokeBefore(subject string, t time.Time) error {
+	return fs.store.set(subject, t, nil)
+}
+
+// Cutoff implements SubjectCutoff.
+func (fs *FileSubjectCutoff) Cutoff(subject string) (time.Time, error) {
+	t, _ := fs.store.get(subject)
+
+	return t, nil
+}
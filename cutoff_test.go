@@ -0,0 +1,94 @@
+package jwt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubjectCutoff(t *testing.T) {
+	assert := assert.New(t)
+
+	issuedAt := time.Now().Add(-time.Hour)
+
+	token, err := Sign(Payload{"foo": "bar"}, "key", &SignOption{
+		Subject:   "testSubject",
+		ExpiresIn: 2 * time.Hour,
+		Clock:     func() time.Time { return issuedAt },
+	})
+
+	assert.Nil(err)
+
+	t.Run("Should return ErrTokenIssuedBeforeCutoff when issued before cutoff", func(t *testing.T) {
+		sc := NewMemorySubjectCutoff()
+
+		_, _, err := Verify(token, "key", &VerifyOption{SubjectCutoff: sc})
+
+		assert.Nil(err)
+		assert.Nil(sc.RevokeBefore("testSubject", time.Now()))
+
+		_, _, err = Verify(token, "key", &VerifyOption{SubjectCutoff: sc})
+
+		assert.Equal(ErrTokenIssuedBeforeCutoff, err)
+	})
+
+	t.Run("Should pass when issued after cutoff", func(t *testing.T) {
+		sc := NewMemorySubjectCutoff()
+
+		assert.Nil(sc.RevokeBefore("testSubject", issuedAt.Add(-time.Minute)))
+
+		_, _, err := Verify(token, "key", &VerifyOption{SubjectCutoff: sc})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should pass when cutoff is for another subject", func(t *testing.T) {
+		sc := NewMemorySubjectCutoff()
+
+		assert.Nil(sc.RevokeBefore("otherSubject", time.Now()))
+
+		_, _, err := Verify(token, "key", &VerifyOption{SubjectCutoff: sc})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should return ErrTokenExpired rather than cutoff error when expired", func(t *testing.T) {
+		sc := NewMemorySubjectCutoff()
+
+		assert.Nil(sc.RevokeBefore("testSubject", time.Now()))
+
+		_, _, err := Verify(token, "key", &VerifyOption{
+			SubjectCutoff: sc,
+			Clock:         func() time.Time { return issuedAt.Add(3 * time.Hour) },
+		})
+
+		assert.Equal(ErrTokenExpired, err)
+	})
+
+	t.Run("Should persist cutoffs to file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "jwt")
+
+		assert.Nil(err)
+
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "cutoffs.json")
+
+		sc, err := NewFileSubjectCutoff(path)
+
+		assert.Nil(err)
+		assert.Nil(sc.RevokeBefore("testSubject", time.Now()))
+
+		sc, err = NewFileSubjectCutoff(path)
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{SubjectCutoff: sc})
+
+		assert.Equal(ErrTokenIssuedBeforeCutoff, err)
+	})
+}
@@ -3,37 +3,88 @@ package jwt
 import (
 	"bytes"
 	"encoding/base64"
-	"encoding/json"
 )
 
-func decode(token []byte) (header map[string]interface{}, payload map[string]interface{}, err error) {
+func decode(token []byte, opt *VerifyOption) (header map[string]interface{}, payload map[string]interface{}, err error) {
 	segments := bytes.Split(token, periodBytes)
 
 	if len(segments) != 3 {
 		return nil, nil, ErrInvalidToken
 	}
 
-	if header, err = decodeSegment(segments[0]); err != nil {
+	if header, err = decodeSegment(segments[0], opt); err != nil {
 		return nil, nil, err
 	}
 
-	if payload, err = decodeSegment(segments[1]); err != nil {
+	if payload, err = decodeSegment(segments[1], opt); err != nil {
 		return nil, nil, err
 	}
 
 	return header, payload, nil
 }
 
-func decodeSegment(segment []byte) (m map[string]interface{}, err error) {
-	s, err := base64.StdEncoding.DecodeString(string(segment))
+// parsedToken represents a token whose segments have been decoded.
+type parsedToken struct {
+	header    Header
+	payload   Payload
+	content   []byte
+	signature []byte
+}
+
+func parse(token []byte, opt *VerifyOption) (pt *parsedToken, err error) {
+	pt = &parsedToken{}
+
+	if pt.header, pt.payload, err = decode(token, opt); err != nil {
+		return nil, err
+	}
+
+	i := bytes.LastIndexByte(token, '.')
+
+	pt.content = token[:i]
+
+	if pt.signature, err = decodeBase64(token[i+1:]); err != nil {
+		return nil, err
+	}
+
+	return pt, nil
+}
+
+// decodeBase64 decodes a token segment, which is unpadded base64url as RFC
+// 7515 requires, or padded standard base64 as produced by Sign by default.
+func decodeBase64(segment []byte) ([]byte, error) {
+	if b, err := base64.RawURLEncoding.Strict().DecodeString(string(segment)); err == nil {
+		return b, nil
+	}
+
+	return base64.StdEncoding.Strict().DecodeString(string(segment))
+}
+
+// decodeSegment decodes the base64 encoded JSON object segment with the Codec
+// of opt, or strictly if opt.Strict is not nil. opt can be nil.
+func decodeSegment(segment []byte, opt *VerifyOption) (m map[string]interface{}, err error) {
+	s, err := decodeBase64(segment)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if err := json.Unmarshal(s, &m); err != nil {
+	if opt == nil {
+		opt = &VerifyOption{}
+	}
+
+	if opt.Strict != nil {
+		return decodeStrict(s, opt.Strict)
+	}
+
+	if err := jsonCodec(opt.Codec).Unmarshal(s, &m); err != nil {
 		return nil, err
 	}
 
 	return
 }
+
+// Decode returns the decoded header and payload of the given token WITHOUT
+// verifying its signature, use Verify for untrusted tokens.
+func Decode(token []byte) (Header, Payload, error) {
+	return decode(token, nil)
+}
@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,7 +14,7 @@ func TestDecode(t *testing.T) {
 	assert := assert.New(t)
 
 	t.Run("Should return ErrInvalidToken when token is invalid", func(t *testing.T) {
-		_, _, err := decode([]byte("a.b"))
+		_, _, err := decode([]byte("a.b"), nil)
 
 		assert.Equal(ErrInvalidToken, err)
 	})
@@ -32,7 +35,7 @@ func TestDecode(t *testing.T) {
 
 		signed, err := Sign(custom, "key", opt)
 
-		header, payload, err := decode(signed)
+		header, payload, err := decode(signed, nil)
 
 		assert.Nil(err)
 		assert.Equal(2, len(header))
@@ -51,8 +54,54 @@ func TestDecode(t *testing.T) {
 	})
 
 	t.Run("Should return error when decodeSegment with not valid json", func(t *testing.T) {
-		_, err := decodeSegment(nil)
+		_, err := decodeSegment(nil, nil)
 
 		assert.NotNil(err)
 	})
+	t.Run("Should decode without verifying signature", func(t *testing.T) {
+		signed, err := Sign(map[string]interface{}{"foo": "bar"}, "key", nil)
+
+		assert.Nil(err)
+
+		header, payload, err := Decode(append(signed, 'x'))
+
+		assert.Nil(err)
+		assert.Equal("JWT", header["typ"])
+		assert.Equal("bar", payload["foo"])
+	})
+
+	t.Run("Should decode unpadded base64url segments", func(t *testing.T) {
+		token := rawURLToken(t, Header{"alg": "HS256", "typ": "JWT"}, Payload{"foo": "~~~>>>???"}, "key")
+
+		assert.False(strings.ContainsAny(string(token), "+/="))
+
+		_, payload, err := Verify(token, "key", &VerifyOption{IngoreExpiration: true})
+
+		assert.Nil(err)
+		assert.Equal("~~~>>>???", payload["foo"])
+	})
+}
+
+// rawURLToken builds a token as other implementations do, encoding its
+// segments with unpadded base64url as RFC 7515 requires.
+func rawURLToken(t *testing.T, header Header, payload Payload, key interface{}) []byte {
+	encode := func(v interface{}) string {
+		b, err := json.Marshal(v)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	content := encode(header) + "." + encode(payload)
+
+	signature, err := algImpMap[Algorithm(header["alg"].(string))].sign([]byte(content), key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return []byte(content + "." + base64.RawURLEncoding.EncodeToString(signature))
 }
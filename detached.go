@@ -0,0 +1,169 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+)
+
+// SignDetached signs arbitrary content and returns a JWS with detached
+// content as described in RFC 7515 Appendix F, whose payload segment is
+// empty, so that the content is transferred separately. Only Algorithm,
+// Header, CertificateChain, UnencodedPayload and RawURLEncoding of opt are
+// used, and no "typ" header is set.
+func SignDetached(content []byte, secretOrPrivateKey interface{}, opt *SignOption) (token []byte, err error) {
+	if opt == nil {
+		opt = &SignOption{}
+	}
+
+	if opt.Algorithm == "" {
+		opt.Algorithm = HS256
+	}
+
+	if secretOrPrivateKey == nil {
+		return nil, ErrEmptySecretOrPrivateKey
+	}
+
+	if len(opt.CertificateChain) > 0 && !certificateMatchesKey(opt.CertificateChain[0], secretOrPrivateKey) {
+		return nil, ErrInvalidKeyType
+	}
+
+	h := map[string]interface{}{"alg": opt.Algorithm}
+
+	if opt.UnencodedPayload {
+		h["b64"] = false
+		h["crit"] = []string{"b64"}
+	}
+
+	headerJSON, err := mergeHeader(h, opt)
+
+	if err != nil {
+		return
+	}
+
+	enc := base64.StdEncoding
+
+	if opt.RawURLEncoding {
+		enc = base64.RawURLEncoding
+	}
+
+	hBase64 := []byte(enc.EncodeToString(headerJSON))
+
+	sigBase64, err := signContent(detachedSigningInput(hBase64, content, opt.UnencodedPayload, enc), secretOrPrivateKey, opt.Algorithm, enc)
+
+	if err != nil {
+		return
+	}
+
+	return bytes.Join([][]byte{hBase64, nil, sigBase64}, periodBytes), nil
+}
+
+// VerifyDetached verifies the JWS with detached content produced by
+// SignDetached against the given content, and returns its header. The
+// "b64" header is honored, and the content is encoded with unpadded
+// base64url as RFC 7515 requires, or padded standard base64 as produced by
+// SignDetached by default. Algorithm, Clock and the key sources of opt are
+// used, while the claims related options are not since the content is not
+// a JWT payload.
+func VerifyDetached(token, content []byte, secretOrPrivateKey interface{}, opt *VerifyOption) (header Header, err error) {
+	if opt == nil {
+		opt = &VerifyOption{}
+	}
+
+	if opt.Algorithm == "" {
+		opt.Algorithm = HS256
+	}
+
+	ai, ok := algImpMap[opt.Algorithm]
+
+	if !ok {
+		return nil, ErrInvalidAlgorithm
+	}
+
+	if opt.Limits != nil {
+		if err = opt.Limits.checkSize(token); err != nil {
+			return nil, err
+		}
+	}
+
+	segments := bytes.Split(token, periodBytes)
+
+	if len(segments) != 3 || len(segments[1]) != 0 {
+		return nil, ErrInvalidToken
+	}
+
+	if header, err = decodeSegment(segments[0], opt); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := decodeBase64(segments[2])
+
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	unencoded, err := header.unencodedPayload()
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, _, err := resolveKey(header, secretOrPrivateKey, opt, currentTime(opt.Clock))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, enc := range []*base64.Encoding{base64.RawURLEncoding, base64.StdEncoding} {
+		if err = ai.verify(detachedSigningInput(segments[0], content, unencoded, enc), signature, key); err == nil || unencoded {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	if err = header.checkCritical(nil, true, opt.CriticalHeaders); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// unencodedPayload reports whether the "b64" header is false, which is only
+// valid when "b64" is also listed in the "crit" header.
+func (h Header) unencodedPayload() (bool, error) {
+	v, ok := h["b64"]
+
+	if !ok {
+		return false, nil
+	}
+
+	b64, ok := v.(bool)
+
+	if !ok || !h.isCritical("b64") {
+		return false, ErrInvalidCriticalHeader
+	}
+
+	return !b64, nil
+}
+
+func (h Header) isCritical(name string) bool {
+	crit, _ := h["crit"].([]interface{})
+
+	for _, v := range crit {
+		if v == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func detachedSigningInput(hBase64, content []byte, unencoded bool, enc *base64.Encoding) []byte {
+	if !unencoded {
+		content = []byte(enc.EncodeToString(content))
+	}
+
+	return bytes.Join([][]byte{hBase64, content}, periodBytes)
+}
@@ -0,0 +1,107 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetached(t *testing.T) {
+	assert := assert.New(t)
+
+	content := []byte(`{"event":"push","ref":"refs/heads/master"}`)
+
+	t.Run("Should sign and verify detached content", func(t *testing.T) {
+		token, err := SignDetached(content, "key", &SignOption{Header: Header{"kid": "testKid"}})
+
+		assert.Nil(err)
+
+		segments := bytes.Split(token, periodBytes)
+
+		assert.Equal(3, len(segments))
+		assert.Empty(segments[1])
+
+		header, err := VerifyDetached(token, content, "key", nil)
+
+		assert.Nil(err)
+		assert.Equal("testKid", header["kid"])
+		assert.Nil(header["typ"])
+
+		_, err = VerifyDetached(token, append(content, ' '), "key", nil)
+
+		assert.Equal(ErrInvalidSignature, err)
+	})
+
+	t.Run("Should verify detached content signed by other implementations", func(t *testing.T) {
+		// The content encodes to "+/" in base64 and "-_" in base64url.
+		binary := []byte{0xfb, 0xff, 0xbf}
+		hBase64 := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`))
+
+		signature, err := algImpMap[HS256].sign([]byte(hBase64+"."+base64.RawURLEncoding.EncodeToString(binary)), "key")
+
+		assert.Nil(err)
+
+		token := []byte(hBase64 + ".." + base64.RawURLEncoding.EncodeToString(signature))
+
+		_, err = VerifyDetached(token, binary, "key", nil)
+
+		assert.Nil(err)
+
+		token, err = SignDetached(binary, "key", &SignOption{RawURLEncoding: true})
+
+		assert.Nil(err)
+		assert.Equal(hBase64+".."+base64.RawURLEncoding.EncodeToString(signature), string(token))
+
+		_, err = VerifyDetached(token, binary, "key", nil)
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should sign and verify unencoded content", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+		assert.Nil(err)
+
+		token, err := SignDetached(content, key, &SignOption{Algorithm: RS256, UnencodedPayload: true})
+
+		assert.Nil(err)
+
+		header, err := VerifyDetached(token, content, &key.PublicKey, &VerifyOption{Algorithm: RS256})
+
+		assert.Nil(err)
+		assert.Equal(false, header["b64"])
+		assert.Equal([]interface{}{"b64"}, header["crit"])
+
+		_, err = VerifyDetached(token, []byte("tampered"), &key.PublicKey, &VerifyOption{Algorithm: RS256})
+
+		assert.Equal(ErrInvalidSignature, err)
+	})
+
+	t.Run("Should return ErrInvalidCriticalHeader when b64 is not critical", func(t *testing.T) {
+		hBase64 := []byte(base64.StdEncoding.EncodeToString([]byte(`{"alg":"HS256","b64":false}`)))
+
+		sigBase64, err := signContent(detachedSigningInput(hBase64, content, true, base64.StdEncoding), "key", HS256, base64.StdEncoding)
+
+		assert.Nil(err)
+
+		token := bytes.Join([][]byte{hBase64, nil, sigBase64}, periodBytes)
+
+		_, err = VerifyDetached(token, content, "key", nil)
+
+		assert.Equal(ErrInvalidCriticalHeader, err)
+	})
+
+	t.Run("Should return ErrInvalidToken when payload is not detached", func(t *testing.T) {
+		token, err := Sign(Payload{"foo": "bar"}, "key", nil)
+
+		assert.Nil(err)
+
+		_, err = VerifyDetached(token, content, "key", nil)
+
+		assert.Equal(ErrInvalidToken, err)
+	})
+}
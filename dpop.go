@@ -0,0 +1,317 @@
+package jwt
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DPoPType is the "typ" header of DPoP proofs (RFC 9449).
+const DPoPType = "dpop+jwt"
+
+// DefaultDPoPMaxAge is how long after its "iat" a DPoP proof is accepted when
+// DPoPOption.MaxAge is zero.
+const DefaultDPoPMaxAge = 5 * time.Minute
+
+// DPoPProofOption represents the options of SignDPoPProof.
+type DPoPProofOption struct {
+	// Algorithm is the RSA algorithm to sign the proof with, RS256 will be
+	// used if it is empty.
+	Algorithm Algorithm
+	// Method and URL are the HTTP method and target URI of the request, the
+	// query and fragment of URL are dropped.
+	Method string
+	URL    string
+	// AccessToken is the access token sent with the request, whose hash will
+	// be included in the "ath" claim if it is not empty.
+	AccessToken string
+	// Nonce is the nonce provided by the server, which will be included in
+	// the "nonce" claim if it is not empty.
+	Nonce string
+	// Clock returns the time used as "iat" of the proof, time.Now will be
+	// used if it is nil.
+	Clock func() time.Time
+}
+
+// DPoPOption represents the options of VerifyDPoPProof.
+type DPoPOption struct {
+	// Method and URL are the HTTP method and target URI of the request the
+	// proof is sent with. URL is compared with "htu" after normalization,
+	// ignoring its query and fragment.
+	Method string
+	URL    string
+	// Algorithms specifies the accepted algorithms, RS256, RS384 and RS512 will
+	// be accepted if it is empty.
+	Algorithms []Algorithm
+	// AccessToken is the access token sent with the request. If it is not
+	// empty, "ath" must match it and the "cnf" claim of AccessTokenClaims
+	// must contain the "jkt" thumbprint of the key of the proof.
+	AccessToken       string
+	AccessTokenClaims Payload
+	// Nonce is the nonce provided by the server, which "nonce" must match if
+	// it is not empty.
+	Nonce string
+	// MaxAge specifies how long after its "iat" the proof is accepted,
+	// DefaultDPoPMaxAge will be used if it is zero.
+	MaxAge time.Duration
+	// ClockTolerance specifies the time duration to tolerate when checking
+	// the "iat" of the proof.
+	ClockTolerance time.Duration
+	// Clock returns the current time, time.Now will be used if it is nil.
+	Clock func() time.Time
+	// ReplayCache records the "jti" of accepted proofs to reject replays,
+	// replays are not detected if it is nil.
+	ReplayCache ReplayCache
+}
+
+// ReplayCache represents the record of used proof identifiers.
+type ReplayCache interface {
+	// Add records id until expiresAt, and reports whether it was not already
+	// recorded. It must be atomic.
+	Add(id string, expiresAt time.Time) (bool, error)
+}
+
+// MemoryReplayCache is a ReplayCache which keeps the record in memory.
+type MemoryReplayCache struct {
+	store *timeStore
+}
+
+// NewMemoryReplayCache returns a new empty MemoryReplayCache.
+func NewMemoryReplayCache() *MemoryReplayCache {
+	store, _ := newTimeStore("")
+
+	return &MemoryReplayCache{store: store}
+}
+
+// Add implements ReplayCache.
+func (mc *MemoryReplayCache) Add(id string, expiresAt time.Time) (bool, error) {
+	return mc.store.add(id, expiresAt, isStaleRevocation)
+}
+
+var dpopClaims = []ClaimRule{
+	{Name: "jti", Required: true, Type: ClaimString},
+	{Name: "htm", Required: true, Type: ClaimString},
+	{Name: "htu", Required: true, Type: ClaimString},
+	{Name: "iat", Required: true, Type: ClaimNumber},
+	{Name: "ath", Type: ClaimString},
+	{Name: "nonce", Type: ClaimString},
+}
+
+// SignDPoPProof signs a DPoP proof for the request described by opt with
+// the given RSA private key, whose public key is embedded in the "jwk"
+// header.
+func SignDPoPProof(privateKey interface{}, opt *DPoPProofOption) ([]byte, error) {
+	if opt == nil || opt.Method == "" || opt.URL == "" {
+		return nil, fmt.Errorf("%w: method and url are required", ErrInvalidDPoPProof)
+	}
+
+	alg := opt.Algorithm
+
+	if alg == "" {
+		alg = RS256
+	}
+
+	jwk, err := NewJWK(privateKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	htu, err := normalizeHTU(opt.URL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	jti, err := randomID()
+
+	if err != nil {
+		return nil, err
+	}
+
+	payload := Payload{"jti": jti, "htm": opt.Method, "htu": htu}
+
+	if opt.AccessToken != "" {
+		payload["ath"] = accessTokenHash(opt.AccessToken)
+	}
+
+	if opt.Nonce != "" {
+		payload["nonce"] = opt.Nonce
+	}
+
+	return Sign(payload, privateKey, &SignOption{
+		Algorithm:      alg,
+		Type:           DPoPType,
+		Header:         Header{"jwk": map[string]interface{}(jwk.Public())},
+		RawURLEncoding: true,
+		Clock:          opt.Clock,
+	})
+}
+
+// VerifyDPoPProof validates the DPoP proof following RFC 9449 section 4.3,
+// and returns the RFC 7638 thumbprint of its key, which the "cnf" claim of
+// access tokens issued for the proof should contain as "jkt". Errors of the
+// DPoP specific checks wrap ErrInvalidDPoPProof.
+func VerifyDPoPProof(proof []byte, opt *DPoPOption) (jkt string, err error) {
+	if opt == nil || opt.Method == "" || opt.URL == "" {
+		return "", fmt.Errorf("%w: method and url are required", ErrInvalidDPoPProof)
+	}
+
+	header, _, err := Decode(proof)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDPoPProof, err)
+	}
+
+	alg, _ := header["alg"].(string)
+	algorithms := opt.Algorithms
+
+	if len(algorithms) == 0 {
+		algorithms = []Algorithm{RS256, RS384, RS512}
+	}
+
+	if isHMAC(Algorithm(alg)) || !containsAlgorithm(algorithms, Algorithm(alg)) {
+		return "", fmt.Errorf("%w: algorithm %q is not accepted", ErrInvalidDPoPProof, alg)
+	}
+
+	m, ok := header["jwk"].(map[string]interface{})
+
+	if !ok {
+		return "", fmt.Errorf("%w: jwk is missing", ErrInvalidDPoPProof)
+	}
+
+	if _, ok = m["d"]; ok {
+		return "", fmt.Errorf("%w: jwk contains a private key", ErrInvalidDPoPProof)
+	}
+
+	key, err := asymmetricJWKKey(JWK(m), ErrInvalidDPoPProof)
+
+	if err != nil {
+		return "", err
+	}
+
+	if jkt, err = JWK(m).Thumbprint(); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDPoPProof, err)
+	}
+
+	maxAge := opt.MaxAge
+
+	if maxAge == 0 {
+		maxAge = DefaultDPoPMaxAge
+	}
+
+	_, _, err = Verify(proof, key, &VerifyOption{
+		Algorithm:        Algorithm(alg),
+		Types:            []string{DPoPType},
+		IngoreExpiration: true,
+		MaxAge:           maxAge,
+		ClockTolerance:   opt.ClockTolerance,
+		Clock:            opt.Clock,
+		Claims:           dpopClaims,
+		Validators: []Validator{func(ctx context.Context, header Header, payload Payload) error {
+			return opt.check(payload, jkt, maxAge)
+		}},
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return jkt, nil
+}
+
+// check validates the claims of the proof whose key has the thumbprint jkt,
+// and records its "jti" last so that rejected proofs do not consume it.
+func (opt *DPoPOption) check(payload Payload, jkt string, maxAge time.Duration) error {
+	if payload["htm"] != opt.Method {
+		return fmt.Errorf("%w: htm does not match", ErrInvalidDPoPProof)
+	}
+
+	expected, err := normalizeHTU(opt.URL)
+
+	if err != nil {
+		return err
+	}
+
+	if htu, err := normalizeHTU(payload["htu"].(string)); err != nil || htu != expected {
+		return fmt.Errorf("%w: htu does not match", ErrInvalidDPoPProof)
+	}
+
+	if opt.Nonce != "" && payload["nonce"] != opt.Nonce {
+		return fmt.Errorf("%w: nonce does not match", ErrInvalidDPoPProof)
+	}
+
+	if opt.AccessToken != "" {
+		ath, _ := payload["ath"].(string)
+
+		if subtle.ConstantTimeCompare([]byte(ath), []byte(accessTokenHash(opt.AccessToken))) != 1 {
+			return fmt.Errorf("%w: ath does not match", ErrInvalidDPoPProof)
+		}
+
+		cnf, _ := opt.AccessTokenClaims["cnf"].(map[string]interface{})
+
+		if cnf == nil || cnf["jkt"] != jkt {
+			return fmt.Errorf("%w: access token is not bound to the key", ErrInvalidDPoPProof)
+		}
+	}
+
+	if opt.ReplayCache != nil {
+		iat, _ := payload.iat()
+		added, err := opt.ReplayCache.Add(payload["jti"].(string), iat.Add(maxAge+opt.ClockTolerance))
+
+		if err != nil {
+			return err
+		}
+
+		if !added {
+			return fmt.Errorf("%w: proof is replayed", ErrInvalidDPoPProof)
+		}
+	}
+
+	return nil
+}
+
+// DPoPProofFromRequest returns the DPoP proof of the request, which must
+// have exactly one DPoP header.
+func DPoPProofFromRequest(r *http.Request) ([]byte, error) {
+	values := r.Header.Values("DPoP")
+
+	if len(values) != 1 || values[0] == "" {
+		return nil, fmt.Errorf("%w: exactly one DPoP header is required", ErrInvalidDPoPProof)
+	}
+
+	return []byte(values[0]), nil
+}
+
+// accessTokenHash returns the "ath" of the access token.
+func accessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// normalizeHTU returns the URL without query and fragment after the
+// normalization RFC 9449 requires when comparing "htu".
+func normalizeHTU(rawURL string) (string, error) {
+	u, ok := normalizeURL(rawURL)
+
+	if !ok {
+		return "", fmt.Errorf("%w: invalid url %q", ErrInvalidDPoPProof, rawURL)
+	}
+
+	return u, nil
+}
+
+func containsAlgorithm(algorithms []Algorithm, alg Algorithm) bool {
+	for _, a := range algorithms {
+		if a == alg {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,214 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDPoP(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+	assert.Nil(err)
+
+	jwk, err := NewJWK(&key.PublicKey)
+
+	assert.Nil(err)
+
+	thumbprint, err := jwk.Thumbprint()
+
+	assert.Nil(err)
+
+	proofOpt := &DPoPProofOption{
+		Method:      "POST",
+		URL:         "https://Server.Example.com:443/token?x=1#f",
+		AccessToken: "access-token",
+		Nonce:       "nonce",
+	}
+
+	opt := func() *DPoPOption {
+		return &DPoPOption{
+			Method:            "POST",
+			URL:               "https://server.example.com/token",
+			AccessToken:       "access-token",
+			AccessTokenClaims: Payload{"cnf": map[string]interface{}{"jkt": thumbprint}},
+			Nonce:             "nonce",
+			ReplayCache:       NewMemoryReplayCache(),
+		}
+	}
+
+	t.Run("Should sign and verify DPoP proof", func(t *testing.T) {
+		proof, err := SignDPoPProof(key, proofOpt)
+
+		assert.Nil(err)
+
+		header, payload, err := Decode(proof)
+
+		assert.Nil(err)
+		assert.Equal(DPoPType, header["typ"])
+		assert.NotContains(header["jwk"], "d")
+		assert.Equal("https://server.example.com/token", payload["htu"])
+		assert.Equal(accessTokenHash("access-token"), payload["ath"])
+
+		jkt, err := VerifyDPoPProof(proof, opt())
+
+		assert.Nil(err)
+		assert.Equal(thumbprint, jkt)
+	})
+
+	t.Run("Should sign and verify base64url proofs", func(t *testing.T) {
+		proof, err := SignDPoPProof(key, proofOpt)
+
+		assert.Nil(err)
+
+		segments := strings.Split(string(proof), ".")
+
+		for _, segment := range segments {
+			_, err := base64.RawURLEncoding.Strict().DecodeString(segment)
+
+			assert.Nil(err, segment)
+		}
+
+		proof = rawURLToken(t, Header{"typ": DPoPType, "alg": "RS256", "jwk": map[string]interface{}(jwk)}, Payload{
+			"jti":   "e1j3V_bKic8-LAEB",
+			"htm":   "POST",
+			"htu":   "https://server.example.com/token",
+			"iat":   time.Now().Unix(),
+			"ath":   accessTokenHash("access-token"),
+			"nonce": "nonce",
+		}, key)
+
+		jkt, err := VerifyDPoPProof(proof, opt())
+
+		assert.Nil(err)
+		assert.Equal(thumbprint, jkt)
+	})
+
+	t.Run("Should return ErrInvalidDPoPProof when proof does not match", func(t *testing.T) {
+		proof, err := SignDPoPProof(key, proofOpt)
+
+		assert.Nil(err)
+
+		for name, modify := range map[string]func(*DPoPOption){
+			"htm":     func(o *DPoPOption) { o.Method = "GET" },
+			"htu":     func(o *DPoPOption) { o.URL = "https://server.example.com/other" },
+			"nonce":   func(o *DPoPOption) { o.Nonce = "other" },
+			"ath":     func(o *DPoPOption) { o.AccessToken = "other" },
+			"cnf":     func(o *DPoPOption) { o.AccessTokenClaims = Payload{"cnf": map[string]interface{}{"jkt": "other"}} },
+			"unbound": func(o *DPoPOption) { o.AccessTokenClaims = nil },
+			"alg":     func(o *DPoPOption) { o.Algorithms = []Algorithm{RS512} },
+		} {
+			o := opt()
+			modify(o)
+
+			_, err := VerifyDPoPProof(proof, o)
+
+			assert.True(errors.Is(err, ErrInvalidDPoPProof), name)
+		}
+	})
+
+	t.Run("Should reject replayed proof", func(t *testing.T) {
+		proof, err := SignDPoPProof(key, proofOpt)
+
+		assert.Nil(err)
+
+		o := opt()
+
+		_, err = VerifyDPoPProof(proof, o)
+
+		assert.Nil(err)
+
+		_, err = VerifyDPoPProof(proof, o)
+
+		assert.True(errors.Is(err, ErrInvalidDPoPProof))
+	})
+
+	t.Run("Should reject proof outside the clock window", func(t *testing.T) {
+		for _, offset := range []time.Duration{-10 * time.Minute, time.Minute} {
+			o := *proofOpt
+			o.Clock = func() time.Time { return time.Now().Add(offset) }
+
+			proof, err := SignDPoPProof(key, &o)
+
+			assert.Nil(err)
+
+			_, err = VerifyDPoPProof(proof, opt())
+
+			assert.NotNil(err)
+		}
+	})
+
+	t.Run("Should reject proof with private or symmetric key", func(t *testing.T) {
+		private, err := NewJWK(key)
+
+		assert.Nil(err)
+
+		proof, err := Sign(Payload{"jti": "id", "htm": "POST", "htu": "https://server.example.com/token"}, key, &SignOption{
+			Algorithm: RS256,
+			Type:      DPoPType,
+			Header:    Header{"jwk": map[string]interface{}(private)},
+		})
+
+		assert.Nil(err)
+
+		_, err = VerifyDPoPProof(proof, &DPoPOption{Method: "POST", URL: "https://server.example.com/token"})
+
+		assert.True(errors.Is(err, ErrInvalidDPoPProof))
+
+		proof, err = Sign(Payload{"jti": "id"}, "key", &SignOption{Type: DPoPType})
+
+		assert.Nil(err)
+
+		_, err = VerifyDPoPProof(proof, &DPoPOption{Method: "POST", URL: "https://server.example.com/token"})
+
+		assert.True(errors.Is(err, ErrInvalidDPoPProof))
+	})
+
+	t.Run("Should require exactly one DPoP header", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/token", nil)
+
+		_, err := DPoPProofFromRequest(r)
+
+		assert.True(errors.Is(err, ErrInvalidDPoPProof))
+
+		r.Header.Add("DPoP", "a")
+
+		proof, err := DPoPProofFromRequest(r)
+
+		assert.Nil(err)
+		assert.Equal([]byte("a"), proof)
+
+		r.Header.Add("DPoP", "b")
+
+		_, err = DPoPProofFromRequest(r)
+
+		assert.True(errors.Is(err, ErrInvalidDPoPProof))
+	})
+
+	t.Run("Should normalize htu", func(t *testing.T) {
+		for raw, expected := range map[string]string{
+			"HTTPS://Example.COM":            "https://example.com/",
+			"http://example.com:80/a?b#c":    "http://example.com/a",
+			"https://example.com:8443/a/%7e": "https://example.com:8443/a/%7e",
+			"https://[::1]:8443/":            "https://[::1]:8443/",
+		} {
+			htu, err := normalizeHTU(raw)
+
+			assert.Nil(err)
+			assert.Equal(expected, htu)
+		}
+
+		_, err := normalizeHTU("/relative")
+
+		assert.True(errors.Is(err, ErrInvalidDPoPProof))
+	})
+}
@@ -0,0 +1,54 @@
+package grpcauth
+
+import (
+	"context"
+
+	"github.com/DavidCai1993/jwt"
+)
+
+// Credentials implements credentials.PerRPCCredentials by signing a token
+// with jwt.Sign for every call.
+type Credentials struct {
+	// Key is the secret or private key passed to jwt.Sign.
+	Key interface{}
+	// Payload is the custom claims of the tokens.
+	Payload jwt.Payload
+	// SignOption is the option passed to jwt.Sign. If its Audience is empty,
+	// the URI of the called service will be used.
+	SignOption *jwt.SignOption
+	// Insecure allows the credentials to be sent over connections without
+	// transport security.
+	Insecure bool
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (c *Credentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	var opt jwt.SignOption
+
+	if c.SignOption != nil {
+		opt = *c.SignOption
+	}
+
+	if opt.Audience == "" && len(uri) > 0 {
+		opt.Audience = uri[0]
+	}
+
+	payload := jwt.Payload{}
+
+	for k, v := range c.Payload {
+		payload[k] = v
+	}
+
+	token, err := jwt.Sign(payload, c.Key, &opt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{MetadataKey: "Bearer " + string(token)}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (c *Credentials) RequireTransportSecurity() bool {
+	return !c.Insecure
+}
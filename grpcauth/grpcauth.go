@@ -0,0 +1,198 @@
+// Package grpcauth provides gRPC interceptors which authenticate calls with
+// JSON web tokens, and PerRPCCredentials which attach them.
+package grpcauth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/DavidCai1993/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataKey is the metadata key carrying the token, in the form of
+// "Bearer <token>".
+const MetadataKey = "authorization"
+
+type contextKey int
+
+const (
+	headerKey contextKey = iota
+	payloadKey
+)
+
+// KeyFunc returns the secret or key used to verify the token of the call.
+type KeyFunc func(ctx context.Context) (interface{}, error)
+
+// StaticKey returns a KeyFunc which always returns the given key.
+func StaticKey(key interface{}) KeyFunc {
+	return func(context.Context) (interface{}, error) {
+		return key, nil
+	}
+}
+
+// VerifyFunc verifies the token with the given key and option, such as
+// jwt.VerifyContext and jwt.VerifyAccessTokenContext.
+type VerifyFunc func(ctx context.Context, token []byte, key interface{}, opt *jwt.VerifyOption) (jwt.Header, jwt.Payload, error)
+
+// AuthorizeFunc decides whether the verified token is allowed to call the
+// method, the call is rejected with codes.PermissionDenied if it returns an
+// error.
+type AuthorizeFunc func(ctx context.Context, fullMethod string, header jwt.Header, payload jwt.Payload) error
+
+// Option represents the options of Authenticator.
+type Option struct {
+	// VerifyOption is the option passed to jwt.VerifyContext along with the
+	// context of the call, it is copied for every call.
+	VerifyOption *jwt.VerifyOption
+	// Verify verifies the token, jwt.VerifyContext will be used if it is nil.
+	// Use jwt.VerifyAccessTokenContext to accept only RFC 9068 access tokens.
+	Verify VerifyFunc
+	// Authorize is called after the token is verified if it is not nil.
+	Authorize AuthorizeFunc
+}
+
+// Authenticator verifies the token in the metadata of incoming calls and
+// stores the verified header and payload in the context.
+type Authenticator struct {
+	keyFunc KeyFunc
+	opt     Option
+}
+
+// New returns an Authenticator which verifies tokens with the key returned
+// by keyFunc.
+func New(keyFunc KeyFunc, opt *Option) *Authenticator {
+	a := &Authenticator{keyFunc: keyFunc}
+
+	if opt != nil {
+		a.opt = *opt
+	}
+
+	if a.opt.Verify == nil {
+		a.opt.Verify = jwt.VerifyContext
+	}
+
+	return a
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor which
+// authenticates unary calls.
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor which
+// authenticates streaming calls.
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	token, ok := tokenFromMetadata(ctx)
+
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	key, err := a.keyFunc(ctx)
+
+	// The failure of the key source is not the fault of the client, and its
+	// detail is not disclosed.
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "verification key unavailable")
+	}
+
+	var opt jwt.VerifyOption
+
+	if a.opt.VerifyOption != nil {
+		opt = *a.opt.VerifyOption
+	}
+
+	header, payload, err := a.opt.Verify(ctx, []byte(token), key, &opt)
+
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if a.opt.Authorize != nil {
+		if err = a.opt.Authorize(ctx, fullMethod, header, payload); err != nil {
+			if _, ok := status.FromError(err); ok {
+				return nil, err
+			}
+
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+
+	return NewContext(ctx, header, payload), nil
+}
+
+func tokenFromMetadata(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+
+	if !ok {
+		return "", false
+	}
+
+	values := md.Get(MetadataKey)
+
+	if len(values) != 1 {
+		return "", false
+	}
+
+	if len(values[0]) <= 7 || !strings.EqualFold(values[0][:7], "Bearer ") {
+		return "", false
+	}
+
+	return values[0][7:], true
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
+
+// NewContext returns a copy of ctx which carries the given verified header
+// and payload.
+func NewContext(ctx context.Context, header jwt.Header, payload jwt.Payload) context.Context {
+	ctx = context.WithValue(ctx, headerKey, header)
+
+	return context.WithValue(ctx, payloadKey, payload)
+}
+
+// HeaderFromContext returns the verified header stored in ctx.
+func HeaderFromContext(ctx context.Context) (jwt.Header, bool) {
+	header, ok := ctx.Value(headerKey).(jwt.Header)
+
+	return header, ok
+}
+
+// PayloadFromContext returns the verified payload stored in ctx.
+func PayloadFromContext(ctx context.Context) (jwt.Payload, bool) {
+	payload, ok := ctx.Value(payloadKey).(jwt.Payload)
+
+	return payload, ok
+}
@@ -0,0 +1,230 @@
+package grpcauth
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DavidCai1993/jwt"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if _, ok := PayloadFromContext(ctx); !ok {
+		return nil, errors.New("missing payload")
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (healthServer) Watch(req *grpc_health_v1.HealthCheckRequest, ws grpc_health_v1.Health_WatchServer) error {
+	payload, ok := PayloadFromContext(ws.Context())
+
+	if !ok || payload["foo"] != "bar" {
+		return errors.New("missing payload")
+	}
+
+	return ws.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING})
+}
+
+func TestAuthenticator(t *testing.T) {
+	assert := assert.New(t)
+
+	a := New(StaticKey("key"), &Option{
+		VerifyOption: &jwt.VerifyOption{Issuer: "testIssuer"},
+		Authorize: func(ctx context.Context, fullMethod string, header jwt.Header, payload jwt.Payload) error {
+			if payload["sub"] == "forbidden" {
+				return errors.New("forbidden")
+			}
+
+			return nil
+		},
+	})
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(a.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(a.StreamServerInterceptor()),
+	)
+
+	grpc_health_v1.RegisterHealthServer(server, healthServer{})
+
+	go server.Serve(lis)
+
+	defer server.Stop()
+
+	dial := func(creds *Credentials) grpc_health_v1.HealthClient {
+		opts := []grpc.DialOption{
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+				return lis.Dial()
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		}
+
+		if creds != nil {
+			opts = append(opts, grpc.WithPerRPCCredentials(creds))
+		}
+
+		conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+
+		assert.Nil(err)
+
+		return grpc_health_v1.NewHealthClient(conn)
+	}
+
+	creds := func(key string, opt *jwt.SignOption) *Credentials {
+		return &Credentials{
+			Key:        key,
+			Payload:    jwt.Payload{"foo": "bar"},
+			SignOption: opt,
+			Insecure:   true,
+		}
+	}
+
+	signOpt := &jwt.SignOption{Issuer: "testIssuer", ExpiresIn: time.Minute}
+
+	t.Run("Should authenticate unary and stream calls", func(t *testing.T) {
+		client := dial(creds("key", signOpt))
+
+		res, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+		assert.Nil(err)
+		assert.Equal(grpc_health_v1.HealthCheckResponse_SERVING, res.Status)
+
+		stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+		assert.Nil(err)
+
+		res, err = stream.Recv()
+
+		assert.Nil(err)
+		assert.Equal(grpc_health_v1.HealthCheckResponse_SERVING, res.Status)
+	})
+
+	t.Run("Should return Unauthenticated when token is missing", func(t *testing.T) {
+		_, err := dial(nil).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+		assert.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Should return Unauthenticated when token is invalid", func(t *testing.T) {
+		client := dial(creds("key1", signOpt))
+
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+		assert.Equal(codes.Unauthenticated, status.Code(err))
+
+		stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+		assert.Nil(err)
+
+		_, err = stream.Recv()
+
+		assert.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Should return PermissionDenied when Authorize failed", func(t *testing.T) {
+		client := dial(creds("key", &jwt.SignOption{Issuer: "testIssuer", Subject: "forbidden", ExpiresIn: time.Minute}))
+
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+		assert.Equal(codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestAuthenticatorVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	a := New(StaticKey("key"), &Option{
+		VerifyOption: &jwt.VerifyOption{Issuer: "testIssuer", Audience: "testAudience"},
+		Verify:       jwt.VerifyAccessTokenContext,
+	})
+
+	authenticate := func(token []byte) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "Bearer "+string(token)))
+
+		_, err := a.authenticate(ctx, "/test.Service/Method")
+
+		return err
+	}
+
+	accessOpt := &jwt.AccessTokenOption{
+		Issuer:    "testIssuer",
+		Audience:  []string{"testAudience"},
+		Subject:   "alice",
+		ClientID:  "client",
+		ExpiresIn: time.Minute,
+	}
+
+	t.Run("Should authenticate access token", func(t *testing.T) {
+		token, err := jwt.SignAccessToken(nil, "key", accessOpt)
+
+		assert.Nil(err)
+		assert.Nil(authenticate(token))
+	})
+
+	t.Run("Should return Unauthenticated when access token expired", func(t *testing.T) {
+		o := *accessOpt
+		o.Clock = func() time.Time { return time.Now().Add(-2 * time.Minute) }
+
+		token, err := jwt.SignAccessToken(nil, "key", &o)
+
+		assert.Nil(err)
+		assert.Equal(codes.Unauthenticated, status.Code(authenticate(token)))
+	})
+
+	t.Run("Should return Unavailable without detail when key is unavailable", func(t *testing.T) {
+		a := New(func(context.Context) (interface{}, error) {
+			return nil, errors.New("secret detail")
+		}, nil)
+
+		token, err := jwt.SignAccessToken(nil, "key", accessOpt)
+
+		assert.Nil(err)
+
+		_, err = a.authenticate(metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "Bearer "+string(token))), "/test.Service/Method")
+
+		assert.Equal(codes.Unavailable, status.Code(err))
+		assert.NotContains(err.Error(), "secret detail")
+	})
+
+	t.Run("Should return Unauthenticated when token is not an access token", func(t *testing.T) {
+		token, err := jwt.Sign(jwt.Payload{}, "key", &jwt.SignOption{Issuer: "testIssuer", Audience: "testAudience", ExpiresIn: time.Minute})
+
+		assert.Nil(err)
+		assert.Equal(codes.Unauthenticated, status.Code(authenticate(token)))
+	})
+}
+
+func TestCredentials(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &Credentials{Key: "key", Payload: jwt.Payload{"foo": "bar"}}
+
+	assert.True(c.RequireTransportSecurity())
+
+	md, err := c.GetRequestMetadata(context.Background(), "https://example.com/pkg.Service")
+
+	assert.Nil(err)
+
+	_, payload, err := jwt.Verify([]byte(md[MetadataKey][7:]), "key", &jwt.VerifyOption{
+		Audience:         "https://example.com/pkg.Service",
+		IngoreExpiration: true,
+	})
+
+	assert.Nil(err)
+	assert.Equal("bar", payload["foo"])
+}
@@ -0,0 +1,347 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultJKUCacheTTL is how long a fetched JWK set is cached when
+// JKUOption.CacheTTL is zero.
+const DefaultJKUCacheTTL = 10 * time.Minute
+
+// DefaultJKUNegativeCacheTTL is how long a failed fetch is cached when
+// JKUOption.NegativeCacheTTL is zero.
+const DefaultJKUNegativeCacheTTL = time.Minute
+
+// DefaultJKUCacheSize is the maximum number of cached JWK sets when
+// JKUOption.CacheSize is zero.
+const DefaultJKUCacheSize = 64
+
+const maxJWKSetSize = 1 << 20
+
+// JKUOption represents the options of fetching the verification key from
+// the JWK set referenced by the "jku" header. It caches the fetched sets, so
+// the same JKUOption should be reused across calls of Verify.
+type JKUOption struct {
+	// AllowedPrefixes specifies the URL prefixes the "jku" header should
+	// start with, like "https://idp.example.com/". Prefixes should end with
+	// "/" to avoid matching other hosts.
+	AllowedPrefixes []string
+	// Client is used to fetch the JWK sets, a client with a 10 seconds timeout
+	// will be used if it is nil.
+	Client *http.Client
+	// CacheTTL specifies how long a fetched JWK set is cached.
+	CacheTTL time.Duration
+	// NegativeCacheTTL specifies how long a failed fetch is cached, so that
+	// tokens referencing an unavailable set do not trigger a fetch each.
+	NegativeCacheTTL time.Duration
+	// CacheSize specifies the maximum number of cached JWK sets, the entries
+	// expiring first are evicted when it is exceeded.
+	CacheSize int
+
+	mu    sync.Mutex
+	cache map[string]*jwkSetCacheEntry
+}
+
+// jwkSetCacheEntry is the result of fetching a JWK set, concurrent fetches of
+// the same URL wait for done instead of fetching again.
+type jwkSetCacheEntry struct {
+	done      chan struct{}
+	fetching  bool
+	keys      []JWK
+	err       error
+	expiresAt time.Time
+}
+
+var defaultJKUClient = &http.Client{Timeout: 10 * time.Second}
+
+// embeddedJWKKey returns the key in the "jwk" header if its thumbprint is
+// one of the trusted thumbprints.
+func (h Header) embeddedJWKKey(trusted []string) (interface{}, error) {
+	m, ok := h["jwk"].(map[string]interface{})
+
+	if !ok {
+		return nil, fmt.Errorf("%w: jwk is not an object", ErrInvalidJWK)
+	}
+
+	jwk := JWK(m)
+
+	thumbprint, err := jwk.Thumbprint()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJWK, err)
+	}
+
+	for _, t := range trusted {
+		if t == thumbprint {
+			return asymmetricJWKKey(jwk, ErrInvalidJWK)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: thumbprint %s is not trusted", ErrInvalidJWK, thumbprint)
+}
+
+// key returns the key selected by the "kid" header from the JWK set which
+// the "jku" header references.
+func (o *JKUOption) key(h Header) (interface{}, error) {
+	jku, ok := h["jku"].(string)
+
+	if !ok {
+		return nil, fmt.Errorf("%w: jku is not a string", ErrInvalidJKU)
+	}
+
+	normalized, ok := o.allowedURL(jku)
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not allowed", ErrInvalidJKU, jku)
+	}
+
+	keys, err := o.fetch(normalized)
+
+	if err != nil {
+		return nil, err
+	}
+
+	kid, hasKid := h["kid"].(string)
+
+	if !hasKid && len(keys) == 1 {
+		return asymmetricJWKKey(keys[0], ErrInvalidJKU)
+	}
+
+	for _, k := range keys {
+		if hasKid && k["kid"] == kid {
+			return asymmetricJWKKey(k, ErrInvalidJKU)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no key matches kid %q", ErrInvalidJKU, kid)
+}
+
+// allowedURL returns the normalized URL jku if it starts with one of the
+// allowed prefixes. Percent-encoded dots, slashes and backslashes are refused
+// since servers may decode them into segments which leave the prefix.
+func (o *JKUOption) allowedURL(jku string) (string, bool) {
+	normalized, ok := normalizeURL(jku)
+
+	if !ok {
+		return "", false
+	}
+
+	lower := strings.ToLower(normalized)
+
+	for _, encoded := range []string{"%2e", "%2f", "%5c"} {
+		if strings.Contains(lower, encoded) {
+			return "", false
+		}
+	}
+
+	for _, prefix := range o.AllowedPrefixes {
+		if prefix != "" && strings.HasPrefix(normalized, prefix) {
+			return normalized, true
+		}
+	}
+
+	return "", false
+}
+
+// fetch returns the JWK set at the normalized URL jku from the cache, or
+// fetches it without holding the lock, sharing the result with concurrent
+// calls for the same URL.
+func (o *JKUOption) fetch(jku string) ([]JWK, error) {
+	o.mu.Lock()
+
+	entry, ok := o.cache[jku]
+
+	if !ok || (!entry.fetching && !time.Now().Before(entry.expiresAt)) {
+		entry = &jwkSetCacheEntry{done: make(chan struct{}), fetching: true}
+
+		o.store(jku, entry)
+		o.mu.Unlock()
+
+		keys, err := o.fetchSet(jku)
+
+		o.mu.Lock()
+		entry.keys, entry.err, entry.fetching = keys, err, false
+		entry.expiresAt = time.Now().Add(o.ttl(err))
+		o.mu.Unlock()
+
+		close(entry.done)
+	} else {
+		o.mu.Unlock()
+
+		<-entry.done
+	}
+
+	return entry.keys, entry.err
+}
+
+// store adds entry to the cache, evicting expired entries and then the
+// entries expiring first when the cache is full. It must be called with the
+// lock held.
+func (o *JKUOption) store(jku string, entry *jwkSetCacheEntry) {
+	if o.cache == nil {
+		o.cache = map[string]*jwkSetCacheEntry{}
+	}
+
+	size := o.CacheSize
+
+	if size <= 0 {
+		size = DefaultJKUCacheSize
+	}
+
+	now := time.Now()
+
+	for k, e := range o.cache {
+		if !e.fetching && !now.Before(e.expiresAt) {
+			delete(o.cache, k)
+		}
+	}
+
+	for len(o.cache) >= size {
+		var oldest string
+
+		for k, e := range o.cache {
+			if !e.fetching && (oldest == "" || e.expiresAt.Before(o.cache[oldest].expiresAt)) {
+				oldest = k
+			}
+		}
+
+		if oldest == "" {
+			break
+		}
+
+		delete(o.cache, oldest)
+	}
+
+	o.cache[jku] = entry
+}
+
+func (o *JKUOption) ttl(err error) time.Duration {
+	if err != nil {
+		if o.NegativeCacheTTL != 0 {
+			return o.NegativeCacheTTL
+		}
+
+		return DefaultJKUNegativeCacheTTL
+	}
+
+	if o.CacheTTL != 0 {
+		return o.CacheTTL
+	}
+
+	return DefaultJKUCacheTTL
+}
+
+func (o *JKUOption) fetchSet(jku string) ([]JWK, error) {
+	client := o.Client
+
+	if client == nil {
+		client = defaultJKUClient
+	}
+
+	// Redirects must stay within the allowed prefixes, otherwise an open
+	// redirect on an allowed host would let anyone supply the key.
+	c := *client
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if _, ok := o.allowedURL(req.URL.String()); !ok {
+			return fmt.Errorf("%w: redirect to %s is not allowed", ErrInvalidJKU, req.URL)
+		}
+
+		if client.CheckRedirect != nil {
+			return client.CheckRedirect(req, via)
+		}
+
+		if len(via) >= 10 {
+			return fmt.Errorf("%w: stopped after 10 redirects", ErrInvalidJKU)
+		}
+
+		return nil
+	}
+
+	res, err := c.Get(jku)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJKU, err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: fetching %s responded %s", ErrInvalidJKU, jku, res.Status)
+	}
+
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+
+	if err = json.NewDecoder(io.LimitReader(res.Body, maxJWKSetSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJKU, err)
+	}
+
+	return set.Keys, nil
+}
+
+// normalizeURL returns the absolute URL without user information, query and
+// fragment after the normalization of RFC 3986 section 6.2.2 and 6.2.3.
+func normalizeURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+
+	if err != nil || !u.IsAbs() || u.Host == "" || u.User != nil {
+		return "", false
+	}
+
+	scheme, host, port := strings.ToLower(u.Scheme), strings.ToLower(u.Hostname()), u.Port()
+
+	if (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+		port = ""
+	}
+
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	if port != "" {
+		host += ":" + port
+	}
+
+	return scheme + "://" + host + removeDotSegments(u.EscapedPath()), true
+}
+
+// removeDotSegments resolves the "." and ".." segments of the absolute path
+// p, keeping its trailing slash.
+func removeDotSegments(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	cleaned := path.Clean(p)
+
+	if cleaned != "/" && (strings.HasSuffix(p, "/") || strings.HasSuffix(p, "/.") || strings.HasSuffix(p, "/..")) {
+		cleaned += "/"
+	}
+
+	return cleaned
+}
+
+// asymmetricJWKKey returns the public key of jwk, refusing secrets which
+// would let anyone forge a token carrying its own key.
+func asymmetricJWKKey(jwk JWK, sentinel error) (interface{}, error) {
+	if jwk["kty"] == "oct" {
+		return nil, fmt.Errorf("%w: symmetric keys are not allowed", sentinel)
+	}
+
+	key, err := jwk.Key()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", sentinel, err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,300 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedJWK(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+	assert.Nil(err)
+
+	jwk, err := NewJWK(&key.PublicKey)
+
+	assert.Nil(err)
+
+	thumbprint, err := jwk.Thumbprint()
+
+	assert.Nil(err)
+
+	token, err := Sign(Payload{"foo": "bar"}, key, &SignOption{
+		Algorithm: RS256,
+		ExpiresIn: time.Minute,
+		Header:    Header{"jwk": jwk},
+	})
+
+	assert.Nil(err)
+
+	t.Run("Should verify with trusted embedded key", func(t *testing.T) {
+		_, payload, err := Verify(token, nil, &VerifyOption{
+			Algorithm:             RS256,
+			TrustedJWKThumbprints: []string{thumbprint},
+		})
+
+		assert.Nil(err)
+		assert.Equal("bar", payload["foo"])
+	})
+
+	t.Run("Should verify base64url token with trusted embedded key", func(t *testing.T) {
+		token := rawURLToken(t, Header{"alg": "RS256", "typ": "JWT", "jwk": map[string]interface{}(jwk)},
+			Payload{"foo": "bar", "iat": time.Now().Unix(), "exp": 60}, key)
+
+		_, payload, err := Verify(token, nil, &VerifyOption{
+			Algorithm:             RS256,
+			TrustedJWKThumbprints: []string{thumbprint},
+		})
+
+		assert.Nil(err)
+		assert.Equal("bar", payload["foo"])
+	})
+
+	t.Run("Should return ErrInvalidJWK when embedded key is not trusted", func(t *testing.T) {
+		_, _, err := Verify(token, nil, &VerifyOption{
+			Algorithm:             RS256,
+			TrustedJWKThumbprints: []string{"other"},
+		})
+
+		assert.True(errors.Is(err, ErrInvalidJWK))
+	})
+
+	t.Run("Should ignore embedded key when not opted in", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 1024)
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, &other.PublicKey, &VerifyOption{Algorithm: RS256})
+
+		assert.Equal(ErrInvalidSignature, err)
+	})
+
+	t.Run("Should return ErrInvalidJWK when embedded key is symmetric", func(t *testing.T) {
+		secret, err := NewJWK("key")
+
+		assert.Nil(err)
+
+		thumbprint, err := secret.Thumbprint()
+
+		assert.Nil(err)
+
+		token, err := Sign(Payload{}, "key", &SignOption{Header: Header{"jwk": secret}})
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, nil, &VerifyOption{TrustedJWKThumbprints: []string{thumbprint}})
+
+		assert.True(errors.Is(err, ErrInvalidJWK))
+	})
+}
+
+func TestJKU(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+	assert.Nil(err)
+
+	jwk, err := NewJWK(&key.PublicKey)
+
+	assert.Nil(err)
+
+	jwk["kid"] = "testKid"
+
+	var fetched int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/keys/redirect":
+			http.Redirect(w, r, "/keys/jwks.json", http.StatusFound)
+			return
+		case "/keys/open-redirect":
+			http.Redirect(w, r, "/jwks.json", http.StatusFound)
+			return
+		}
+
+		atomic.AddInt32(&fetched, 1)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []JWK{jwk}})
+	}))
+
+	defer server.Close()
+
+	sign := func(jku, kid string) []byte {
+		token, err := Sign(Payload{"foo": "bar"}, key, &SignOption{
+			Algorithm: RS256,
+			ExpiresIn: time.Minute,
+			Header:    Header{"jku": jku, "kid": kid},
+		})
+
+		assert.Nil(err)
+
+		return token
+	}
+
+	t.Run("Should verify with fetched key and cache the set", func(t *testing.T) {
+		opt := &JKUOption{AllowedPrefixes: []string{server.URL + "/"}}
+
+		for i := 0; i < 2; i++ {
+			_, payload, err := Verify(sign(server.URL+"/jwks.json", "testKid"), nil, &VerifyOption{
+				Algorithm: RS256,
+				JKU:       opt,
+			})
+
+			assert.Nil(err)
+			assert.Equal("bar", payload["foo"])
+		}
+
+		assert.Equal(int32(1), atomic.LoadInt32(&fetched))
+	})
+
+	t.Run("Should return ErrInvalidJKU when URL is not allowed", func(t *testing.T) {
+		opt := &JKUOption{AllowedPrefixes: []string{"https://idp.example.com/"}}
+
+		_, _, err := Verify(sign(server.URL+"/jwks.json", "testKid"), nil, &VerifyOption{
+			Algorithm: RS256,
+			JKU:       opt,
+		})
+
+		assert.True(errors.Is(err, ErrInvalidJKU))
+	})
+
+	t.Run("Should return ErrInvalidJKU when kid is unknown", func(t *testing.T) {
+		opt := &JKUOption{AllowedPrefixes: []string{server.URL + "/"}}
+
+		_, _, err := Verify(sign(server.URL+"/jwks.json", "otherKid"), nil, &VerifyOption{
+			Algorithm: RS256,
+			JKU:       opt,
+		})
+
+		assert.True(errors.Is(err, ErrInvalidJKU))
+	})
+	t.Run("Should ignore query when caching the set", func(t *testing.T) {
+		opt := &JKUOption{AllowedPrefixes: []string{server.URL + "/"}}
+		before := atomic.LoadInt32(&fetched)
+
+		for _, jku := range []string{"/jwks.json?x=1", "/jwks.json?x=2", "/jwks.json#x"} {
+			_, _, err := Verify(sign(server.URL+jku, "testKid"), nil, &VerifyOption{
+				Algorithm: RS256,
+				JKU:       opt,
+			})
+
+			assert.Nil(err)
+		}
+
+		assert.Equal(before+1, atomic.LoadInt32(&fetched))
+		assert.Equal(1, len(opt.cache))
+	})
+
+	t.Run("Should fetch once for concurrent verifications", func(t *testing.T) {
+		opt := &JKUOption{AllowedPrefixes: []string{server.URL + "/"}}
+		before := atomic.LoadInt32(&fetched)
+		token := sign(server.URL+"/jwks.json", "testKid")
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_, _, err := Verify(token, nil, &VerifyOption{Algorithm: RS256, JKU: opt})
+
+				assert.Nil(err)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(before+1, atomic.LoadInt32(&fetched))
+	})
+
+	t.Run("Should bound the cache size", func(t *testing.T) {
+		opt := &JKUOption{AllowedPrefixes: []string{server.URL + "/"}, CacheSize: 2}
+
+		for _, jku := range []string{"/a", "/b", "/c"} {
+			_, _, err := Verify(sign(server.URL+jku, "testKid"), nil, &VerifyOption{
+				Algorithm: RS256,
+				JKU:       opt,
+			})
+
+			assert.Nil(err)
+		}
+
+		assert.Equal(2, len(opt.cache))
+	})
+
+	t.Run("Should cache failed fetches", func(t *testing.T) {
+		var failed int32
+
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&failed, 1)
+
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		defer failing.Close()
+
+		opt := &JKUOption{AllowedPrefixes: []string{failing.URL + "/"}}
+
+		for i := 0; i < 2; i++ {
+			_, _, err := Verify(sign(failing.URL+"/jwks.json", "testKid"), nil, &VerifyOption{
+				Algorithm: RS256,
+				JKU:       opt,
+			})
+
+			assert.True(errors.Is(err, ErrInvalidJKU))
+		}
+
+		assert.Equal(int32(1), atomic.LoadInt32(&failed))
+	})
+	t.Run("Should resolve dot segments before matching prefixes", func(t *testing.T) {
+		opt := &JKUOption{AllowedPrefixes: []string{server.URL + "/keys/"}}
+
+		for _, jku := range []string{"/keys/../jwks.json", "/keys/%2e%2e/jwks.json", "/keys/%2E%2E%2Fjwks.json", "/keys/..%5Cjwks.json"} {
+			_, _, err := Verify(sign(server.URL+jku, "testKid"), nil, &VerifyOption{
+				Algorithm: RS256,
+				JKU:       opt,
+			})
+
+			assert.True(errors.Is(err, ErrInvalidJKU), jku)
+		}
+
+		_, _, err := Verify(sign(server.URL+"/other/../keys/./jwks.json", "testKid"), nil, &VerifyOption{
+			Algorithm: RS256,
+			JKU:       opt,
+		})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should follow redirects only within allowed prefixes", func(t *testing.T) {
+		opt := &JKUOption{AllowedPrefixes: []string{server.URL + "/keys/"}}
+
+		_, _, err := Verify(sign(server.URL+"/keys/open-redirect", "testKid"), nil, &VerifyOption{
+			Algorithm: RS256,
+			JKU:       opt,
+		})
+
+		assert.True(errors.Is(err, ErrInvalidJKU))
+
+		_, _, err = Verify(sign(server.URL+"/keys/redirect", "testKid"), nil, &VerifyOption{
+			Algorithm: RS256,
+			JKU:       opt,
+		})
+
+		assert.Nil(err)
+	})
+}
@@ -1,10 +1,8 @@
 package jwt
 
 import (
-	"bytes"
 	"crypto"
 	"crypto/hmac"
-	"encoding/base64"
 	"hash"
 )
 
@@ -37,28 +35,16 @@ func (ha hmacAlgImp) sign(content []byte, secret interface{}) ([]byte, error) {
 	return h.Sum(nil), nil
 }
 
-func (ha hmacAlgImp) verify(token []byte, secret interface{}) (header Header, payload Payload, err error) {
-	if header, payload, err = decode(token); err != nil {
-		return
-	}
-
-	signatureReceive, err := base64.StdEncoding.DecodeString(string(bytes.Split(token, periodBytes)[2]))
+func (ha hmacAlgImp) verify(content, signature []byte, secret interface{}) error {
+	signatureExpect, err := ha.sign(content, secret)
 
 	if err != nil {
-		return
+		return err
 	}
 
-	signatureExpect, err := ha.sign(token[0:bytes.LastIndexByte(token, '.')], secret)
-
-	if err != nil {
-		return
-	}
-
-	ok := hmac.Equal(signatureExpect, signatureReceive)
-
-	if !ok {
-		return nil, nil, ErrInvalidSignature
+	if !hmac.Equal(signatureExpect, signature) {
+		return ErrInvalidSignature
 	}
 
-	return
+	return nil
 }
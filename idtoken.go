@@ -0,0 +1,268 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// IDToken represents the standard claims of a verified OpenID Connect ID
+// token.
+type IDToken struct {
+	Issuer          string
+	Subject         string
+	Audience        []string
+	ExpiresAt       time.Time
+	IssuedAt        time.Time
+	AuthTime        time.Time
+	Nonce           string
+	ACR             string
+	AMR             []string
+	AuthorizedParty string
+	AccessTokenHash string
+	CodeHash        string
+	// Header and Claims are the decoded header and payload of the token.
+	Header Header
+	Claims Payload
+}
+
+// IDTokenVerifier verifies ID tokens issued to a client following the steps
+// of OpenID Connect Core 1.0 section 3.1.3.7.
+type IDTokenVerifier struct {
+	// Issuer is the issuer identifier of the OpenID provider, which must match
+	// "iss" exactly.
+	Issuer string
+	// ClientID is the client identifier which must be one of the audiences.
+	ClientID string
+	// Key is the key to verify the signature with.
+	Key interface{}
+	// TrustedAudiences specifies the audiences trusted besides ClientID,
+	// tokens with other audiences will be rejected.
+	TrustedAudiences []string
+	// VerifyOption is the option passed to Verify, whose Algorithm is RS256 if
+	// it is empty. Its Issuer, Audience and Subject are ignored.
+	VerifyOption *VerifyOption
+}
+
+// IDTokenOption represents the values of the authentication request and
+// response to check the ID token against.
+type IDTokenOption struct {
+	// Nonce is the nonce sent in the authentication request, which "nonce"
+	// must match if it is not empty.
+	Nonce string
+	// MaxAge is the max_age sent in the authentication request, if it is not
+	// zero "auth_time" is required and must not be older than it.
+	MaxAge time.Duration
+	// AccessToken is the access token issued along with the ID token, which
+	// "at_hash" must match if it is not empty. "at_hash" is required for the
+	// implicit flow, which is not checked.
+	AccessToken string
+	// Code is the authorization code issued along with the ID token, which
+	// "c_hash" must match if it is not empty.
+	Code string
+}
+
+var idTokenClaims = []ClaimRule{
+	{Name: "iss", Required: true, Type: ClaimString},
+	{Name: "sub", Required: true, Type: ClaimString},
+	{Name: "aud", Required: true},
+	{Name: "exp", Required: true, Type: ClaimNumber},
+	{Name: "iat", Required: true, Type: ClaimNumber},
+	{Name: "auth_time", Type: ClaimNumber},
+	{Name: "nonce", Type: ClaimString},
+	{Name: "acr", Type: ClaimString},
+	{Name: "amr", Type: ClaimArray},
+	{Name: "azp", Type: ClaimString},
+	{Name: "at_hash", Type: ClaimString},
+	{Name: "c_hash", Type: ClaimString},
+}
+
+// Verify verifies the ID token and returns its standard claims. "exp" is the
+// absolute expiration time as OpenID Connect requires. Errors of the OpenID
+// Connect specific checks wrap ErrInvalidIDToken.
+func (v *IDTokenVerifier) Verify(token []byte, opt *IDTokenOption) (*IDToken, error) {
+	var o VerifyOption
+
+	if v.VerifyOption != nil {
+		o = *v.VerifyOption
+	}
+
+	if opt == nil {
+		opt = &IDTokenOption{}
+	}
+
+	if v.Issuer == "" || v.ClientID == "" {
+		return nil, fmt.Errorf("%w: issuer and client id are required", ErrInvalidIDToken)
+	}
+
+	if o.Algorithm == "" {
+		o.Algorithm = RS256
+	}
+
+	o.Issuer, o.Audience, o.Subject = v.Issuer, v.ClientID, ""
+	o.AllowMissingType = true
+	o.IngoreExpiration, o.AbsoluteExpiration = false, true
+	o.Claims = append(append([]ClaimRule{}, idTokenClaims...), o.Claims...)
+	o.Validators = append([]Validator{v.validator(o, opt)}, o.Validators...)
+
+	header, payload, err := VerifyContext(context.Background(), token, v.Key, &o)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newIDToken(header, payload), nil
+}
+
+// validator returns the Validator performing the checks of OpenID Connect.
+func (v *IDTokenVerifier) validator(o VerifyOption, opt *IDTokenOption) Validator {
+	return func(ctx context.Context, header Header, payload Payload) error {
+		auds := audiences(payload)
+
+		for _, aud := range auds {
+			if aud != v.ClientID && !containsString(v.TrustedAudiences, aud) {
+				return fmt.Errorf("%w: untrusted audience %s", ErrInvalidIDToken, aud)
+			}
+		}
+
+		azp, hasAzp := payload["azp"].(string)
+
+		if !hasAzp && len(auds) > 1 {
+			return fmt.Errorf("%w: azp is required for multiple audiences", ErrInvalidIDToken)
+		}
+
+		if hasAzp && azp != v.ClientID {
+			return fmt.Errorf("%w: azp does not match", ErrInvalidIDToken)
+		}
+
+		if opt.Nonce != "" && payload["nonce"] != opt.Nonce {
+			return fmt.Errorf("%w: nonce does not match", ErrInvalidIDToken)
+		}
+
+		if opt.MaxAge != 0 {
+			authTime, ok := claimNumber(payload["auth_time"])
+
+			if !ok {
+				return fmt.Errorf("%w: auth_time is required", ErrInvalidIDToken)
+			}
+
+			if currentTime(o.Clock).Add(-o.ClockTolerance).After(time.Unix(int64(authTime), 0).Add(opt.MaxAge)) {
+				return fmt.Errorf("%w: authentication is older than max age", ErrInvalidIDToken)
+			}
+		}
+
+		if opt.AccessToken != "" && !checkTokenHash(payload["at_hash"], opt.AccessToken, o.Algorithm) {
+			return fmt.Errorf("%w: at_hash does not match", ErrInvalidIDToken)
+		}
+
+		if opt.Code != "" && !checkTokenHash(payload["c_hash"], opt.Code, o.Algorithm) {
+			return fmt.Errorf("%w: c_hash does not match", ErrInvalidIDToken)
+		}
+
+		return nil
+	}
+}
+
+// TokenHash returns the at_hash or c_hash of value for the ID token signed
+// with alg, which is the base64url encoded left-most half of its hash.
+func TokenHash(value string, alg Algorithm) (string, error) {
+	var h crypto.Hash
+
+	switch alg {
+	case HS256, RS256:
+		h = crypto.SHA256
+	case HS384, RS384:
+		h = crypto.SHA384
+	case HS512, RS512:
+		h = crypto.SHA512
+	default:
+		return "", ErrInvalidAlgorithm
+	}
+
+	hh := h.New()
+
+	hh.Write([]byte(value))
+
+	sum := hh.Sum(nil)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]), nil
+}
+
+func checkTokenHash(claim interface{}, value string, alg Algorithm) bool {
+	received, ok := claim.(string)
+
+	if !ok {
+		return false
+	}
+
+	expected, err := TokenHash(value, alg)
+
+	return err == nil && subtle.ConstantTimeCompare([]byte(received), []byte(expected)) == 1
+}
+
+// audiences returns "aud", which is a string or an array of strings.
+func audiences(payload Payload) []string {
+	if aud, ok := payload["aud"].(string); ok {
+		return []string{aud}
+	}
+
+	auds := []string{}
+
+	if a, ok := payload["aud"].([]interface{}); ok {
+		for _, aud := range a {
+			if s, ok := aud.(string); ok {
+				auds = append(auds, s)
+			}
+		}
+	}
+
+	return auds
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newIDToken(header Header, payload Payload) *IDToken {
+	t := &IDToken{Audience: audiences(payload), Header: header, Claims: payload}
+
+	t.Issuer, _ = payload["iss"].(string)
+	t.Subject, _ = payload["sub"].(string)
+	t.Nonce, _ = payload["nonce"].(string)
+	t.ACR, _ = payload["acr"].(string)
+	t.AuthorizedParty, _ = payload["azp"].(string)
+	t.AccessTokenHash, _ = payload["at_hash"].(string)
+	t.CodeHash, _ = payload["c_hash"].(string)
+	t.ExpiresAt = unixClaim(payload, "exp")
+	t.IssuedAt = unixClaim(payload, "iat")
+	t.AuthTime = unixClaim(payload, "auth_time")
+
+	if amr, ok := payload["amr"].([]interface{}); ok {
+		for _, v := range amr {
+			if s, ok := v.(string); ok {
+				t.AMR = append(t.AMR, s)
+			}
+		}
+	}
+
+	return t
+}
+
+// unixClaim returns the NumericDate claim name, or the zero time if it is
+// missing.
+func unixClaim(payload Payload, name string) time.Time {
+	if v, ok := claimNumber(payload[name]); ok {
+		return time.Unix(int64(v), 0)
+	}
+
+	return time.Time{}
+}
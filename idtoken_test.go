@@ -0,0 +1,148 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDTokenVerifier(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+	assert.Nil(err)
+
+	atHash, err := TokenHash("access-token", RS256)
+
+	assert.Nil(err)
+
+	now := time.Now()
+
+	sign := func(claims Payload) []byte {
+		payload := Payload{
+			"iss":       "https://op.example.com",
+			"sub":       "alice",
+			"aud":       "client",
+			"exp":       now.Add(time.Hour).Unix(),
+			"auth_time": now.Add(-time.Minute).Unix(),
+			"nonce":     "n-0S6_WzA2Mj",
+			"amr":       []string{"pwd", "otp"},
+			"at_hash":   atHash,
+		}
+
+		for k, v := range claims {
+			if v == nil {
+				delete(payload, k)
+			} else {
+				payload[k] = v
+			}
+		}
+
+		token, err := Sign(payload, key, &SignOption{Algorithm: RS256})
+
+		assert.Nil(err)
+
+		return token
+	}
+
+	v := &IDTokenVerifier{Issuer: "https://op.example.com", ClientID: "client", Key: &key.PublicKey}
+
+	t.Run("Should verify ID token and return standard claims", func(t *testing.T) {
+		idToken, err := v.Verify(sign(nil), &IDTokenOption{
+			Nonce:       "n-0S6_WzA2Mj",
+			MaxAge:      time.Hour,
+			AccessToken: "access-token",
+		})
+
+		assert.Nil(err)
+		assert.Equal("alice", idToken.Subject)
+		assert.Equal([]string{"client"}, idToken.Audience)
+		assert.Equal([]string{"pwd", "otp"}, idToken.AMR)
+		assert.Equal(now.Add(time.Hour).Unix(), idToken.ExpiresAt.Unix())
+		assert.Equal(atHash, idToken.AccessTokenHash)
+		assert.Equal("RS256", idToken.Header["alg"])
+	})
+
+	t.Run("Should return ErrInvalidIDToken when checks fail", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			claims Payload
+			opt    *IDTokenOption
+		}{
+			"nonce":          {nil, &IDTokenOption{Nonce: "other"}},
+			"azp missing":    {Payload{"aud": []string{"client", "other"}}, nil},
+			"azp mismatch":   {Payload{"azp": "other"}, nil},
+			"untrusted aud":  {Payload{"aud": []string{"client", "other"}, "azp": "client"}, nil},
+			"max age":        {nil, &IDTokenOption{MaxAge: 30 * time.Second}},
+			"auth_time":      {Payload{"auth_time": nil}, &IDTokenOption{MaxAge: time.Hour}},
+			"at_hash":        {nil, &IDTokenOption{AccessToken: "other"}},
+			"c_hash missing": {nil, &IDTokenOption{Code: "code"}},
+		} {
+			_, err := v.Verify(sign(c.claims), c.opt)
+
+			assert.True(errors.Is(err, ErrInvalidIDToken), name)
+		}
+	})
+
+	t.Run("Should accept trusted audiences with azp", func(t *testing.T) {
+		v := *v
+		v.TrustedAudiences = []string{"other"}
+
+		_, err := v.Verify(sign(Payload{"aud": []string{"client", "other"}, "azp": "client"}), nil)
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should return standard errors of Verify", func(t *testing.T) {
+		_, err := v.Verify(sign(Payload{"exp": now.Add(-time.Minute).Unix()}), nil)
+
+		assert.Equal(ErrTokenExpired, err)
+
+		_, err = v.Verify(sign(Payload{"iss": "https://evil.example.com"}), nil)
+
+		assert.Equal(ErrInvalidReservedClaim, err)
+
+		_, err = v.Verify(sign(Payload{"sub": nil}), nil)
+
+		assert.True(errors.Is(err, ErrInvalidClaim))
+	})
+
+	t.Run("Should verify base64url ID token issued by other providers", func(t *testing.T) {
+		token := rawURLToken(t, Header{"alg": "HS256", "kid": "1"}, Payload{
+			"iss":   "https://op.example.com",
+			"sub":   "248289761001",
+			"aud":   "client",
+			"nonce": "n-0S6_WzA2Mj",
+			"exp":   now.Add(time.Hour).Unix(),
+			"iat":   now.Unix(),
+		}, "secret")
+
+		v := &IDTokenVerifier{
+			Issuer:       "https://op.example.com",
+			ClientID:     "client",
+			Key:          "secret",
+			VerifyOption: &VerifyOption{Algorithm: HS256},
+		}
+
+		idToken, err := v.Verify(token, &IDTokenOption{Nonce: "n-0S6_WzA2Mj"})
+
+		assert.Nil(err)
+		assert.Equal("248289761001", idToken.Subject)
+	})
+
+	t.Run("Should compute token hash", func(t *testing.T) {
+		// Example of OpenID Connect Core 1.0 appendix A.3.
+		hash, err := TokenHash("jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y", RS256)
+
+		assert.Nil(err)
+		assert.Equal("77QmUPtjPfzWtF2AnpK9RQ", hash)
+
+		_, err = TokenHash("token", Algorithm("none"))
+
+		assert.Equal(ErrInvalidAlgorithm, err)
+	})
+}
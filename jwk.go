@@ -0,0 +1,274 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// JWK represents a JSON Web Key (RFC 7517).
+type JWK map[string]interface{}
+
+var jwkPrivateMembers = []string{"d", "p", "q", "dp", "dq", "qi", "k"}
+
+// NewJWK returns the JWK representation of the given key, whose type should
+// be []byte or string for HMAC secrets, *rsa.PrivateKey, *rsa.PublicKey,
+// *ecdsa.PrivateKey, *ecdsa.PublicKey, ed25519.PrivateKey or
+// ed25519.PublicKey.
+func NewJWK(key interface{}) (JWK, error) {
+	switch k := key.(type) {
+	case []byte:
+		return JWK{"kty": "oct", "k": encodeJWKBytes(k)}, nil
+	case string:
+		return JWK{"kty": "oct", "k": encodeJWKBytes([]byte(k))}, nil
+	case *rsa.PublicKey:
+		return JWK{
+			"kty": "RSA",
+			"n":   encodeJWKBytes(k.N.Bytes()),
+			"e":   encodeJWKBytes(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *rsa.PrivateKey:
+		jwk, _ := NewJWK(&k.PublicKey)
+
+		k.Precompute()
+
+		jwk["d"] = encodeJWKBytes(k.D.Bytes())
+
+		if len(k.Primes) == 2 {
+			jwk["p"] = encodeJWKBytes(k.Primes[0].Bytes())
+			jwk["q"] = encodeJWKBytes(k.Primes[1].Bytes())
+			jwk["dp"] = encodeJWKBytes(k.Precomputed.Dp.Bytes())
+			jwk["dq"] = encodeJWKBytes(k.Precomputed.Dq.Bytes())
+			jwk["qi"] = encodeJWKBytes(k.Precomputed.Qinv.Bytes())
+		}
+
+		return jwk, nil
+	case *ecdsa.PublicKey:
+		crv, size := jwkCurve(k.Curve)
+
+		if crv == "" {
+			return nil, ErrInvalidKeyType
+		}
+
+		return JWK{
+			"kty": "EC",
+			"crv": crv,
+			"x":   encodeJWKBytes(k.X.FillBytes(make([]byte, size))),
+			"y":   encodeJWKBytes(k.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case *ecdsa.PrivateKey:
+		jwk, err := NewJWK(&k.PublicKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		_, size := jwkCurve(k.Curve)
+		jwk["d"] = encodeJWKBytes(k.D.FillBytes(make([]byte, size)))
+
+		return jwk, nil
+	case ed25519.PublicKey:
+		return JWK{"kty": "OKP", "crv": "Ed25519", "x": encodeJWKBytes(k)}, nil
+	case ed25519.PrivateKey:
+		jwk, _ := NewJWK(k.Public())
+		jwk["d"] = encodeJWKBytes(k.Seed())
+
+		return jwk, nil
+	default:
+		return nil, ErrInvalidKeyType
+	}
+}
+
+// Public returns a copy of the JWK without its private members.
+func (k JWK) Public() JWK {
+	public := JWK{}
+
+	for name, v := range k {
+		public[name] = v
+	}
+
+	for _, name := range jwkPrivateMembers {
+		delete(public, name)
+	}
+
+	return public
+}
+
+// Key returns the public key represented by the JWK, whose type is
+// *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey, or the secret of
+// "oct" keys as []byte. Private members are ignored.
+func (k JWK) Key() (interface{}, error) {
+	switch k["kty"] {
+	case "oct":
+		return k.bytes("k")
+	case "RSA":
+		n, err := k.bytes("n")
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := k.bytes("e")
+
+		if err != nil {
+			return nil, err
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 || exponent.Int64() < 2 {
+			return nil, fmt.Errorf("%w: invalid RSA exponent", ErrInvalidKeyData)
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		curve, ecdhCurve, size := jwkCurveByName(k["crv"])
+
+		if curve == nil {
+			return nil, fmt.Errorf("%w: unsupported curve %v", ErrInvalidKeyData, k["crv"])
+		}
+
+		x, err := k.bytes("x")
+
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := k.bytes("y")
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("%w: invalid EC coordinates", ErrInvalidKeyData)
+		}
+
+		// crypto/ecdh validates that the point is on the curve.
+		if _, err = ecdhCurve.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKeyData, err)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if k["crv"] != "Ed25519" {
+			return nil, fmt.Errorf("%w: unsupported curve %v", ErrInvalidKeyData, k["crv"])
+		}
+
+		x, err := k.bytes("x")
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid Ed25519 key size", ErrInvalidKeyData)
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %v", ErrInvalidKeyData, k["kty"])
+	}
+}
+
+// Thumbprint returns the base64url encoded SHA-256 JWK thumbprint described
+// in RFC 7638.
+func (k JWK) Thumbprint() (string, error) {
+	var members []string
+
+	switch k["kty"] {
+	case "oct":
+		members = []string{"k", "kty"}
+	case "RSA":
+		members = []string{"e", "kty", "n"}
+	case "EC":
+		members = []string{"crv", "kty", "x", "y"}
+	case "OKP":
+		members = []string{"crv", "kty", "x"}
+	default:
+		return "", fmt.Errorf("%w: unsupported key type %v", ErrInvalidKeyData, k["kty"])
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, name := range members {
+		v, ok := k[name].(string)
+
+		if !ok {
+			return "", fmt.Errorf("%w: missing %s", ErrInvalidKeyData, name)
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		nameJSON, _ := json.Marshal(name)
+		valueJSON, _ := json.Marshal(v)
+
+		buf.Write(nameJSON)
+		buf.WriteByte(':')
+		buf.Write(valueJSON)
+	}
+
+	buf.WriteByte('}')
+
+	sum := sha256.Sum256(buf.Bytes())
+
+	return encodeJWKBytes(sum[:]), nil
+}
+
+func (k JWK) bytes(name string) ([]byte, error) {
+	s, ok := k[name].(string)
+
+	if !ok || s == "" {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidKeyData, name)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s", ErrInvalidKeyData, name)
+	}
+
+	return b, nil
+}
+
+func jwkCurveByName(crv interface{}) (elliptic.Curve, ecdh.Curve, int) {
+	switch crv {
+	case "P-256":
+		return elliptic.P256(), ecdh.P256(), 32
+	case "P-384":
+		return elliptic.P384(), ecdh.P384(), 48
+	case "P-521":
+		return elliptic.P521(), ecdh.P521(), 66
+	default:
+		return nil, nil, 0
+	}
+}
+
+func jwkCurve(curve elliptic.Curve) (string, int) {
+	switch curve {
+	case elliptic.P256():
+		return "P-256", 32
+	case elliptic.P384():
+		return "P-384", 48
+	case elliptic.P521():
+		return "P-521", 66
+	default:
+		return "", 0
+	}
+}
+
+func encodeJWKBytes(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
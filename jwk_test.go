@@ -0,0 +1,185 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJWK(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Should return oct JWK for HMAC secret", func(t *testing.T) {
+		jwk, err := NewJWK("key")
+
+		assert.Nil(err)
+		assert.Equal(JWK{"kty": "oct", "k": "a2V5"}, jwk)
+		assert.Equal(JWK{"kty": "oct"}, jwk.Public())
+	})
+
+	t.Run("Should return RSA JWK", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+		assert.Nil(err)
+
+		jwk, err := NewJWK(key)
+
+		assert.Nil(err)
+		assert.Equal("RSA", jwk["kty"])
+		assert.Equal("AQAB", jwk["e"])
+
+		for _, name := range []string{"n", "d", "p", "q", "dp", "dq", "qi"} {
+			assert.NotEmpty(jwk[name])
+		}
+
+		public, err := NewJWK(&key.PublicKey)
+
+		assert.Nil(err)
+		assert.Equal(public, jwk.Public())
+	})
+
+	t.Run("Should return EC JWK", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+		assert.Nil(err)
+
+		jwk, err := NewJWK(key)
+
+		assert.Nil(err)
+		assert.Equal("EC", jwk["kty"])
+		assert.Equal("P-384", jwk["crv"])
+		assert.Equal(64, len(jwk["x"].(string)))
+		assert.Equal(64, len(jwk["d"].(string)))
+
+		public, err := NewJWK(&key.PublicKey)
+
+		assert.Nil(err)
+		assert.Equal(public, jwk.Public())
+	})
+
+	t.Run("Should return OKP JWK", func(t *testing.T) {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+
+		assert.Nil(err)
+
+		jwk, err := NewJWK(private)
+
+		assert.Nil(err)
+		assert.Equal("OKP", jwk["kty"])
+		assert.Equal("Ed25519", jwk["crv"])
+		assert.Equal(encodeJWKBytes(public), jwk["x"])
+		assert.Equal(encodeJWKBytes(private.Seed()), jwk["d"])
+	})
+
+	t.Run("Should return ErrInvalidKeyType when key is not supported", func(t *testing.T) {
+		_, err := NewJWK(123)
+
+		assert.Equal(ErrInvalidKeyType, err)
+
+		key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+
+		assert.Nil(err)
+
+		_, err = NewJWK(key)
+
+		assert.Equal(ErrInvalidKeyType, err)
+	})
+}
+
+func TestJWKKey(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Should round trip public keys", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+
+		assert.Nil(err)
+
+		ecKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+
+		assert.Nil(err)
+
+		edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+
+		assert.Nil(err)
+
+		for _, key := range []interface{}{&rsaKey.PublicKey, &ecKey.PublicKey, edPublic} {
+			jwk, err := NewJWK(key)
+
+			assert.Nil(err)
+
+			parsed, err := jwk.Key()
+
+			assert.Nil(err)
+			assert.True(parsed.(interface{ Equal(crypto.PublicKey) bool }).Equal(key))
+		}
+
+		jwk, err := NewJWK(rsaKey)
+
+		assert.Nil(err)
+
+		parsed, err := jwk.Key()
+
+		assert.Nil(err)
+		assert.True(rsaKey.PublicKey.Equal(parsed))
+
+		jwk, err = NewJWK("key")
+
+		assert.Nil(err)
+
+		parsed, err = jwk.Key()
+
+		assert.Nil(err)
+		assert.Equal([]byte("key"), parsed)
+	})
+
+	t.Run("Should return ErrInvalidKeyData when JWK is invalid", func(t *testing.T) {
+		for _, jwk := range []JWK{
+			{"kty": "unknown"},
+			{"kty": "RSA", "n": "AQAB"},
+			{"kty": "RSA", "n": "AQAB", "e": "!"},
+			{"kty": "EC", "crv": "P-256", "x": "AQAB", "y": "AQAB"},
+			{"kty": "EC", "crv": "P-256", "x": encodeJWKBytes(make([]byte, 32)), "y": encodeJWKBytes(make([]byte, 32))},
+			{"kty": "OKP", "crv": "X25519", "x": "AQAB"},
+		} {
+			_, err := jwk.Key()
+
+			assert.True(errors.Is(err, ErrInvalidKeyData), jwk)
+		}
+	})
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Should compute RFC 7638 thumbprint", func(t *testing.T) {
+		jwk := JWK{
+			"kty": "RSA",
+			"n":   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			"e":   "AQAB",
+			"alg": "RS256",
+			"kid": "2011-04-29",
+		}
+
+		thumbprint, err := jwk.Thumbprint()
+
+		assert.Nil(err)
+		assert.Equal("NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+	})
+
+	t.Run("Should return ErrInvalidKeyData when member is missing", func(t *testing.T) {
+		_, err := JWK{"kty": "EC", "crv": "P-256", "x": "AQAB"}.Thumbprint()
+
+		assert.True(errors.Is(err, ErrInvalidKeyData))
+
+		_, err = JWK{}.Thumbprint()
+
+		assert.True(errors.Is(err, ErrInvalidKeyData))
+	})
+}
@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -31,25 +32,75 @@ var (
 	ErrEmptySecretOrPrivateKey = errors.New("jwt: empty secret or private key")
 	// ErrInvalidKeyType is returned when the type of given key is wrong.
 	ErrInvalidKeyType = errors.New("jwt: invalid key")
+	// ErrInvalidKeyData is returned when the given key data can not be parsed.
+	ErrInvalidKeyData = errors.New("jwt: invalid key data")
 	// ErrInvalidSignature is returned when the given signature is invalid.
 	ErrInvalidSignature = errors.New("jwt: invalid signature")
-	// ErrInvalidHeaderType is returned when "typ" not found in header and is not
-	// "JWT".
+	// ErrInvalidHeaderType is returned when "typ" not found in header or is not
+	// one of the types given in VerifyOption, which is "JWT" by default.
 	ErrInvalidHeaderType = errors.New("jwt: invalid header type")
+	// ErrInvalidX5C is returned when the certificate chain in the "x5c" header
+	// is missing or invalid.
+	ErrInvalidX5C = errors.New("jwt: invalid x5c header")
+	// ErrInvalidJWK is returned when the key in the "jwk" header is invalid or
+	// not trusted.
+	ErrInvalidJWK = errors.New("jwt: invalid jwk header")
+	// ErrInvalidJKU is returned when the key referenced by the "jku" header can
+	// not be fetched or is not allowed.
+	ErrInvalidJKU = errors.New("jwt: invalid jku header")
+	// ErrInvalidCriticalHeader is returned when the "crit" header or the
+	// headers it lists are invalid or not understood.
+	ErrInvalidCriticalHeader = errors.New("jwt: invalid critical header")
 	// ErrInvalidToken is returned when the formation of the token is not
 	// "XXX.XXX.XXX".
 	ErrInvalidToken = errors.New("jwt: invalid token")
+	// ErrInvalidJSON is returned by strict decoding when the header or payload
+	// has duplicate member names or is nested too deeply.
+	ErrInvalidJSON = errors.New("jwt: invalid json")
+	// ErrTokenTooLarge is returned when the token exceeds the limits given in
+	// VerifyOption.
+	ErrTokenTooLarge = errors.New("jwt: token too large")
 	// ErrInvalidAlgorithm is returned when the algorithm is not support.
 	ErrInvalidAlgorithm = errors.New("jwt: invalid algorithm")
 	// ErrInvalidReservedClaim is returned when the reserved claim dose not match
 	// with the given value in VerifyOption.
 	ErrInvalidReservedClaim = errors.New("jwt: invalid reserved claim")
+	// ErrInvalidClaim is returned when a claim does not meet its ClaimRule in
+	// VerifyOption, it is wrapped by a *ClaimError naming the claim.
+	ErrInvalidClaim = errors.New("jwt: invalid claim")
+	// ErrInvalidAccessToken is returned when an RFC 9068 access token can not
+	// be signed or verified because of missing claims or options.
+	ErrInvalidAccessToken = errors.New("jwt: invalid access token")
+	// ErrInvalidIDToken is returned when an OpenID Connect ID token fails the
+	// checks specific to OpenID Connect.
+	ErrInvalidIDToken = errors.New("jwt: invalid id token")
+	// ErrInvalidDPoPProof is returned when a DPoP proof is malformed or does
+	// not match the request, the access token or the server nonce.
+	ErrInvalidDPoPProof = errors.New("jwt: invalid dpop proof")
 	// ErrPayloadMissingIat is returned when the payload is missing "iat".
 	ErrPayloadMissingIat = errors.New("jwt: payload missing iat")
 	// ErrPayloadMissingExp is returned when the payload is missing "exp".
 	ErrPayloadMissingExp = errors.New("jwt: payload missing exp")
 	// ErrTokenExpired is returned when the token is expired.
 	ErrTokenExpired = errors.New("jwt: token expired")
+	// ErrTokenTooOld is returned when the token was issued longer than the
+	// MaxAge given in VerifyOption ago.
+	ErrTokenTooOld = errors.New("jwt: token exceeded maximum age")
+	// ErrTokenIssuedInFuture is returned when the "iat" of the token is later
+	// than the current time beyond the clock tolerance.
+	ErrTokenIssuedInFuture = errors.New("jwt: token issued in the future")
+	// ErrRefreshExpired is returned by Refresh when the token expired longer
+	// than the grace period ago.
+	ErrRefreshExpired = errors.New("jwt: token expired beyond refresh grace period")
+	// ErrMaxLifetimeExceeded is returned by Refresh when the session of the
+	// token has exceeded the maximum lifetime.
+	ErrMaxLifetimeExceeded = errors.New("jwt: token exceeded maximum lifetime")
+	// ErrTokenRevoked is returned when the token is found in the Revoker given
+	// in VerifyOption.
+	ErrTokenRevoked = errors.New("jwt: token revoked")
+	// ErrTokenIssuedBeforeCutoff is returned when the token was issued before
+	// the cutoff of its subject in the SubjectCutoff given in VerifyOption.
+	ErrTokenIssuedBeforeCutoff = errors.New("jwt: token issued before subject cutoff")
 
 	periodBytes = []byte(".")
 	algImpMap   = map[Algorithm]algorithmImplementation{}
@@ -57,13 +108,15 @@ var (
 
 type algorithmImplementation interface {
 	sign(content []byte, key interface{}) ([]byte, error)
-	verify(signing []byte, key interface{}) (Header, Payload, error)
+	verify(content, signature []byte, key interface{}) error
 }
 
 // Header represents a JWT header.
 type Header map[string]interface{}
 
-func (h Header) hasValidType() bool {
+// hasValidType reports whether "typ" is one of types, which is "JWT" if types
+// is empty.
+func (h Header) hasValidType(types ...string) bool {
 	var (
 		typ      interface{}
 		received string
@@ -78,7 +131,25 @@ func (h Header) hasValidType() bool {
 		return false
 	}
 
-	return received == "JWT"
+	if len(types) == 0 {
+		types = []string{"JWT"}
+	}
+
+	for _, t := range types {
+		if normalizeType(t) == normalizeType(received) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeType lowercases the media type typ and strips its "application/"
+// prefix, as RFC 7515 section 4.1.9 allows the prefix to be omitted.
+func normalizeType(typ string) string {
+	typ = strings.ToLower(typ)
+
+	return strings.TrimPrefix(typ, "application/")
 }
 
 // Payload represents a JWT payload.
@@ -106,6 +177,22 @@ func (p Payload) checkStringClaim(key, expected string) bool {
 	return expected == received
 }
 
+// hasAudience is like checkStringClaim of "aud", but also accepts an array of
+// audiences containing expected.
+func (p Payload) hasAudience(expected string) bool {
+	if auds, ok := p["aud"].([]interface{}); ok && expected != "" {
+		for _, aud := range auds {
+			if aud == expected {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return p.checkStringClaim("aud", expected)
+}
+
 func (p Payload) iat() (t time.Time, err error) {
 	var (
 		iat float64
@@ -117,7 +204,7 @@ func (p Payload) iat() (t time.Time, err error) {
 		return t, ErrPayloadMissingIat
 	}
 
-	if iat, ok = v.(float64); !ok {
+	if iat, ok = claimNumber(v); !ok {
 		return t, ErrPayloadMissingIat
 	}
 
@@ -136,7 +223,7 @@ func (p Payload) expTime() (t time.Time, err error) {
 		return t, ErrPayloadMissingExp
 	}
 
-	if exp, ok = v.(float64); !ok {
+	if exp, ok = claimNumber(v); !ok {
 		return t, ErrPayloadMissingExp
 	}
 
@@ -147,10 +234,66 @@ func (p Payload) expTime() (t time.Time, err error) {
 	return iat.Add(time.Duration(int64(exp * 1e9))), nil
 }
 
-func (p Payload) checkExpiration(tolerance time.Duration) bool {
+// absoluteExpTime returns "exp" as the NumericDate of RFC 7519, which the
+// profiles such as RFC 9068 and OpenID Connect use instead of the lifetime
+// after "iat" Sign produces.
+func (p Payload) absoluteExpTime() (time.Time, error) {
+	exp, ok := claimNumber(p["exp"])
+
+	if !ok {
+		return time.Time{}, ErrPayloadMissingExp
+	}
+
+	return time.Unix(0, int64(exp*1e9)), nil
+}
+
+func (p Payload) checkExpiration(now time.Time, tolerance time.Duration) bool {
 	if exp, err := p.expTime(); err == nil {
-		return time.Now().Add(tolerance).Before(exp)
+		return now.Add(tolerance).Before(exp)
 	}
 
 	return false
 }
+
+// checkAbsoluteExpiration validates "exp" as a NumericDate, which must not
+// be passed beyond tolerance.
+func (p Payload) checkAbsoluteExpiration(now time.Time, tolerance time.Duration) bool {
+	if exp, err := p.absoluteExpTime(); err == nil {
+		return now.Add(-tolerance).Before(exp)
+	}
+
+	return false
+}
+
+// checkIssuedAt validates "iat" against now, which must not be in the future
+// beyond tolerance, nor older than maxAge if it is not zero. "iat" is only
+// required when maxAge is not zero.
+func (p Payload) checkIssuedAt(now time.Time, tolerance, maxAge time.Duration) error {
+	iat, err := p.iat()
+
+	if err != nil {
+		if maxAge == 0 {
+			return nil
+		}
+
+		return err
+	}
+
+	if iat.After(now.Add(tolerance)) {
+		return ErrTokenIssuedInFuture
+	}
+
+	if maxAge != 0 && now.Add(-tolerance).After(iat.Add(maxAge)) {
+		return ErrTokenTooOld
+	}
+
+	return nil
+}
+
+func currentTime(clock func() time.Time) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+
+	return clock()
+}
@@ -22,6 +22,16 @@ func TestHeader(t *testing.T) {
 
 		assert.False(h.hasValidType())
 	})
+
+	t.Run("Should compare typ case insensitively with optional application/ prefix", func(t *testing.T) {
+		var h Header = map[string]interface{}{"typ": "application/AT+JWT"}
+
+		assert.True(h.hasValidType("at+jwt"))
+		assert.True(h.hasValidType("JWT", "Application/at+jwt"))
+		assert.False(h.hasValidType())
+		assert.False(h.hasValidType("dpop+jwt"))
+		assert.True(Header{"typ": "jwt"}.hasValidType())
+	})
 }
 
 func TestPayloadCheckStringClaim(t *testing.T) {
@@ -49,5 +59,34 @@ func TestPayloadCheckStringClaim(t *testing.T) {
 func TestPayloadCheckExpiration(t *testing.T) {
 	assert := assert.New(t)
 	var p Payload = map[string]interface{}{"test": 123}
-	assert.False(p.checkExpiration(1 * time.Second))
+	assert.False(p.checkExpiration(time.Now(), 1*time.Second))
+}
+
+func TestPayloadCheckIssuedAt(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(1000000, 0)
+
+	t.Run("Should pass when iat is missing and MaxAge is zero", func(t *testing.T) {
+		var p Payload = map[string]interface{}{}
+
+		assert.Nil(p.checkIssuedAt(now, 0, 0))
+		assert.Equal(ErrPayloadMissingIat, p.checkIssuedAt(now, 0, time.Hour))
+	})
+
+	t.Run("Should return ErrTokenIssuedInFuture when iat is beyond tolerance", func(t *testing.T) {
+		var p Payload = map[string]interface{}{"iat": float64(now.Unix() + 10)}
+
+		assert.Equal(ErrTokenIssuedInFuture, p.checkIssuedAt(now, 0, 0))
+		assert.Nil(p.checkIssuedAt(now, 10*time.Second, 0))
+	})
+
+	t.Run("Should return ErrTokenTooOld when iat is older than MaxAge", func(t *testing.T) {
+		var p Payload = map[string]interface{}{"iat": float64(now.Unix() - 3600)}
+
+		assert.Equal(ErrTokenTooOld, p.checkIssuedAt(now, 0, 30*time.Minute))
+		assert.Equal(ErrTokenTooOld, p.checkIssuedAt(now, time.Minute, 30*time.Minute))
+		assert.Nil(p.checkIssuedAt(now, 30*time.Minute, 30*time.Minute))
+		assert.Nil(p.checkIssuedAt(now, 0, time.Hour))
+	})
 }
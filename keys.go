@@ -0,0 +1,237 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// ParsePrivateKeyPEM parses the first private key found in the PEM data, in
+// PKCS#1, PKCS#8 or SEC1 form. The returned key is an *rsa.PrivateKey, an
+// *ecdsa.PrivateKey or an ed25519.PrivateKey.
+func ParsePrivateKeyPEM(data []byte) (crypto.PrivateKey, error) {
+	return ParseEncryptedPrivateKeyPEM(data, nil)
+}
+
+// ParseEncryptedPrivateKeyPEM is like ParsePrivateKeyPEM, but decrypts the
+// key with passphrase if it is protected by a passphrase as described in
+// RFC 1423. Encrypted PKCS#8 keys are not supported.
+func ParseEncryptedPrivateKeyPEM(data, passphrase []byte) (crypto.PrivateKey, error) {
+	for {
+		var block *pem.Block
+
+		if block, data = pem.Decode(data); block == nil {
+			return nil, fmt.Errorf("%w: no private key found in PEM data", ErrInvalidKeyData)
+		}
+
+		switch block.Type {
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, fmt.Errorf("%w: encrypted PKCS#8 private keys are not supported", ErrInvalidKeyData)
+		case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+		default:
+			continue
+		}
+
+		der := block.Bytes
+
+		// RFC 1423 encryption is deprecated for being insecure, but is still
+		// produced by many tools.
+		if x509.IsEncryptedPEMBlock(block) {
+			if passphrase == nil {
+				return nil, fmt.Errorf("%w: private key is encrypted", ErrInvalidKeyData)
+			}
+
+			var err error
+
+			if der, err = x509.DecryptPEMBlock(block, passphrase); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidKeyData, err)
+			}
+		}
+
+		return ParsePrivateKeyDER(der)
+	}
+}
+
+// ParsePrivateKeyDER parses a DER encoded private key in PKCS#1, PKCS#8 or
+// SEC1 form.
+func ParsePrivateKeyDER(der []byte) (crypto.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown private key form", ErrInvalidKeyData)
+}
+
+// ParsePublicKeyPEM parses the first public key or certificate found in the
+// PEM data, in PKIX or PKCS#1 form. The returned key is an *rsa.PublicKey, an
+// *ecdsa.PublicKey or an ed25519.PublicKey.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	for {
+		var block *pem.Block
+
+		if block, data = pem.Decode(data); block == nil {
+			return nil, fmt.Errorf("%w: no public key found in PEM data", ErrInvalidKeyData)
+		}
+
+		switch block.Type {
+		case "PUBLIC KEY", "RSA PUBLIC KEY", "CERTIFICATE":
+			return ParsePublicKeyDER(block.Bytes)
+		}
+	}
+}
+
+// ParsePublicKeyDER parses a DER encoded public key in PKIX or PKCS#1 form,
+// or the public key of a DER encoded certificate.
+func ParsePublicKeyDER(der []byte) (crypto.PublicKey, error) {
+	if key, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return key, nil
+	}
+
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		return cert.PublicKey, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown public key form", ErrInvalidKeyData)
+}
+
+// ParseCertificatesPEM parses all the certificates found in the PEM data.
+func ParseCertificatesPEM(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for {
+		var block *pem.Block
+
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKeyData, err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%w: no certificate found in PEM data", ErrInvalidKeyData)
+	}
+
+	return certs, nil
+}
+
+// LoadSigningKey returns the key which Sign requires for alg from data: the
+// data itself for HMAC algorithms, or the PEM encoded private key for the
+// others.
+func LoadSigningKey(alg Algorithm, data []byte) (interface{}, error) {
+	if isHMAC(alg) {
+		return data, nil
+	}
+
+	key, err := ParsePrivateKeyPEM(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = CheckKeyAlgorithm(alg, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// LoadVerifyingKey returns the key which Verify requires for alg from data:
+// the data itself for HMAC algorithms, or the PEM encoded public key,
+// certificate or private key for the others.
+func LoadVerifyingKey(alg Algorithm, data []byte) (interface{}, error) {
+	if isHMAC(alg) {
+		return data, nil
+	}
+
+	key, err := ParsePublicKeyPEM(data)
+
+	if err != nil {
+		private, perr := ParsePrivateKeyPEM(data)
+
+		if perr != nil {
+			return nil, err
+		}
+
+		key = private.(crypto.Signer).Public()
+	}
+
+	if err = CheckKeyAlgorithm(alg, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// CheckKeyAlgorithm returns an error wrapping ErrInvalidKeyType which
+// describes the mismatch if key can not be used with alg.
+func CheckKeyAlgorithm(alg Algorithm, key interface{}) error {
+	var expected string
+
+	switch {
+	case isHMAC(alg):
+		switch key.(type) {
+		case []byte, string:
+			return nil
+		}
+
+		expected = "an HMAC secret"
+	case strings.HasPrefix(string(alg), "RS"):
+		switch key.(type) {
+		case *rsa.PrivateKey, *rsa.PublicKey:
+			return nil
+		}
+
+		expected = "an RSA key"
+	default:
+		return ErrInvalidAlgorithm
+	}
+
+	return fmt.Errorf("%w: %s requires %s, got %s", ErrInvalidKeyType, alg, expected, keyTypeName(key))
+}
+
+func isHMAC(alg Algorithm) bool {
+	return strings.HasPrefix(string(alg), "HS")
+}
+
+func keyTypeName(key interface{}) string {
+	switch key.(type) {
+	case []byte, string:
+		return "an HMAC secret"
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return "an RSA key"
+	case *ecdsa.PrivateKey, *ecdsa.PublicKey:
+		return "an ECDSA key"
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return "an Ed25519 key"
+	default:
+		return fmt.Sprintf("%T", key)
+	}
+}
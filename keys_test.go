@@ -0,0 +1,193 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePEM(typ string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func selfSignedCertificate(key *rsa.PrivateKey) []byte {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	return der
+}
+
+func TestParseKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+
+	assert.Nil(err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	assert.Nil(err)
+
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+
+	assert.Nil(err)
+
+	t.Run("Should parse private keys in PKCS#1, PKCS#8 and SEC1", func(t *testing.T) {
+		pkcs8, err := x509.MarshalPKCS8PrivateKey(edKey)
+
+		assert.Nil(err)
+
+		sec1, err := x509.MarshalECPrivateKey(ecKey)
+
+		assert.Nil(err)
+
+		key, err := ParsePrivateKeyPEM(encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)))
+
+		assert.Nil(err)
+		assert.True(rsaKey.Equal(key))
+
+		key, err = ParsePrivateKeyPEM(encodePEM("PRIVATE KEY", pkcs8))
+
+		assert.Nil(err)
+		assert.True(edKey.Equal(key))
+
+		key, err = ParsePrivateKeyPEM(append(encodePEM("EC PARAMETERS", []byte{6, 8}), encodePEM("EC PRIVATE KEY", sec1)...))
+
+		assert.Nil(err)
+		assert.True(ecKey.Equal(key))
+
+		key, err = ParsePrivateKeyDER(sec1)
+
+		assert.Nil(err)
+		assert.True(ecKey.Equal(key))
+	})
+
+	t.Run("Should parse passphrase protected private key", func(t *testing.T) {
+		block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY",
+			x509.MarshalPKCS1PrivateKey(rsaKey), []byte("passphrase"), x509.PEMCipherAES256)
+
+		assert.Nil(err)
+
+		data := pem.EncodeToMemory(block)
+
+		key, err := ParseEncryptedPrivateKeyPEM(data, []byte("passphrase"))
+
+		assert.Nil(err)
+		assert.True(rsaKey.Equal(key))
+
+		_, err = ParseEncryptedPrivateKeyPEM(data, []byte("wrong"))
+
+		assert.True(errors.Is(err, ErrInvalidKeyData))
+
+		_, err = ParsePrivateKeyPEM(data)
+
+		assert.True(errors.Is(err, ErrInvalidKeyData))
+	})
+
+	t.Run("Should parse public keys in PKIX, PKCS#1 and certificates", func(t *testing.T) {
+		pkix, err := x509.MarshalPKIXPublicKey(edPublic)
+
+		assert.Nil(err)
+
+		key, err := ParsePublicKeyPEM(encodePEM("PUBLIC KEY", pkix))
+
+		assert.Nil(err)
+		assert.True(edPublic.Equal(key))
+
+		key, err = ParsePublicKeyPEM(encodePEM("RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)))
+
+		assert.Nil(err)
+		assert.True(rsaKey.PublicKey.Equal(key))
+
+		cert := selfSignedCertificate(rsaKey)
+
+		key, err = ParsePublicKeyPEM(encodePEM("CERTIFICATE", cert))
+
+		assert.Nil(err)
+		assert.True(rsaKey.PublicKey.Equal(key))
+
+		certs, err := ParseCertificatesPEM(append(encodePEM("CERTIFICATE", cert), encodePEM("CERTIFICATE", cert)...))
+
+		assert.Nil(err)
+		assert.Equal(2, len(certs))
+	})
+
+	t.Run("Should return ErrInvalidKeyData when no key found", func(t *testing.T) {
+		_, err := ParsePrivateKeyPEM([]byte("foo"))
+
+		assert.True(errors.Is(err, ErrInvalidKeyData))
+
+		_, err = ParsePublicKeyPEM(encodePEM("PUBLIC KEY", []byte("foo")))
+
+		assert.True(errors.Is(err, ErrInvalidKeyData))
+
+		_, err = ParseCertificatesPEM(nil)
+
+		assert.True(errors.Is(err, ErrInvalidKeyData))
+	})
+
+	t.Run("Should load keys for algorithm", func(t *testing.T) {
+		private := encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+		key, err := LoadSigningKey(RS256, private)
+
+		assert.Nil(err)
+
+		token, err := Sign(Payload{}, key, &SignOption{Algorithm: RS256})
+
+		assert.Nil(err)
+
+		key, err = LoadVerifyingKey(RS256, private)
+
+		assert.Nil(err)
+		assert.IsType(&rsa.PublicKey{}, key)
+
+		_, _, err = Verify(token, key, &VerifyOption{Algorithm: RS256, IngoreExpiration: true})
+
+		assert.Nil(err)
+
+		key, err = LoadSigningKey(HS256, []byte("key"))
+
+		assert.Nil(err)
+		assert.Equal([]byte("key"), key)
+	})
+
+	t.Run("Should return ErrInvalidKeyType when key does not match algorithm", func(t *testing.T) {
+		sec1, err := x509.MarshalECPrivateKey(ecKey)
+
+		assert.Nil(err)
+
+		key, err := LoadSigningKey(RS256, encodePEM("EC PRIVATE KEY", sec1))
+
+		assert.Nil(key)
+		assert.True(errors.Is(err, ErrInvalidKeyType))
+		assert.Equal("jwt: invalid key: RS256 requires an RSA key, got an ECDSA key", err.Error())
+
+		key, err = LoadVerifyingKey(RS256, encodePEM("EC PRIVATE KEY", sec1))
+
+		assert.Nil(key)
+		assert.True(errors.Is(err, ErrInvalidKeyType))
+
+		err = CheckKeyAlgorithm(HS256, rsaKey)
+
+		assert.Equal("jwt: invalid key: HS256 requires an HMAC secret, got an RSA key", err.Error())
+		assert.Equal(ErrInvalidAlgorithm, CheckKeyAlgorithm("ES256", ecKey))
+	})
+}
@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+)
+
+// LimitOption represents the resource limits of untrusted tokens, which are
+// checked before decoding them. A limit of zero means no limit.
+type LimitOption struct {
+	// MaxTokenSize specifies the maximum length of the token in bytes.
+	MaxTokenSize int
+	// MaxHeaderSize specifies the maximum decoded size of the header in
+	// bytes.
+	MaxHeaderSize int
+	// MaxPayloadSize specifies the maximum decoded size of the payload in
+	// bytes.
+	MaxPayloadSize int
+	// MaxClaims specifies the maximum number of claims in the payload.
+	MaxClaims int
+}
+
+// checkSize checks the sizes of token and its segments without decoding it.
+// The decoded sizes are computed as unpadded base64url, which is the upper
+// bound of padded standard base64 of the same length as well.
+func (l *LimitOption) checkSize(token []byte) error {
+	if l.MaxTokenSize > 0 && len(token) > l.MaxTokenSize {
+		return fmt.Errorf("%w: token is longer than %d bytes", ErrTokenTooLarge, l.MaxTokenSize)
+	}
+
+	first, last := bytes.IndexByte(token, '.'), bytes.LastIndexByte(token, '.')
+
+	if first < 0 || first == last {
+		return nil
+	}
+
+	if l.MaxHeaderSize > 0 && base64.RawURLEncoding.DecodedLen(first) > l.MaxHeaderSize {
+		return fmt.Errorf("%w: header is larger than %d bytes", ErrTokenTooLarge, l.MaxHeaderSize)
+	}
+
+	if l.MaxPayloadSize > 0 && base64.RawURLEncoding.DecodedLen(last-first-1) > l.MaxPayloadSize {
+		return fmt.Errorf("%w: payload is larger than %d bytes", ErrTokenTooLarge, l.MaxPayloadSize)
+	}
+
+	return nil
+}
+
+// checkClaims checks the number of claims of the decoded payload, whose size
+// has been limited by checkSize.
+func (l *LimitOption) checkClaims(payload Payload) error {
+	if l.MaxClaims > 0 && len(payload) > l.MaxClaims {
+		return fmt.Errorf("%w: payload has more than %d claims", ErrTokenTooLarge, l.MaxClaims)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	token, err := Sign(Payload{"foo": "bar", "data": strings.Repeat("x", 512)}, "key", &SignOption{ExpiresIn: time.Minute})
+
+	assert.Nil(err)
+
+	t.Run("Should pass when token is within limits", func(t *testing.T) {
+		_, _, err := Verify(token, "key", &VerifyOption{Limits: &LimitOption{
+			MaxTokenSize:   1024,
+			MaxHeaderSize:  64,
+			MaxPayloadSize: 1024,
+			MaxClaims:      4,
+		}})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should return ErrTokenTooLarge when token exceeds limits", func(t *testing.T) {
+		for _, limits := range []*LimitOption{
+			{MaxTokenSize: 512},
+			{MaxHeaderSize: 16},
+			{MaxPayloadSize: 512},
+			{MaxClaims: 3},
+		} {
+			_, _, err := Verify(token, "key", &VerifyOption{Limits: limits})
+
+			assert.True(errors.Is(err, ErrTokenTooLarge), limits)
+		}
+	})
+
+	t.Run("Should limit decoded size of base64url segments", func(t *testing.T) {
+		header := Header{"alg": "HS256", "typ": "JWT", "kid": "testKid"}
+		headerJSON, err := json.Marshal(header)
+
+		assert.Nil(err)
+		assert.Equal(43, len(headerJSON))
+
+		token := rawURLToken(t, header, Payload{"exp": 60, "iat": time.Now().Unix()}, "key")
+
+		_, _, err = Verify(token, "key", &VerifyOption{Limits: &LimitOption{MaxHeaderSize: 42}})
+
+		assert.True(errors.Is(err, ErrTokenTooLarge))
+
+		_, _, err = Verify(token, "key", &VerifyOption{Limits: &LimitOption{MaxHeaderSize: 43}})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should check sizes before decoding", func(t *testing.T) {
+		_, _, err := Verify([]byte(strings.Repeat("!", 2048)+".."), "key", &VerifyOption{
+			Limits: &LimitOption{MaxHeaderSize: 1024},
+		})
+
+		assert.True(errors.Is(err, ErrTokenTooLarge))
+	})
+
+	t.Run("Should return ErrTokenTooLarge when detached token exceeds limits", func(t *testing.T) {
+		token, err := SignDetached([]byte("content"), "key", nil)
+
+		assert.Nil(err)
+
+		_, err = VerifyDetached(token, []byte("content"), "key", &VerifyOption{Limits: &LimitOption{MaxTokenSize: 16}})
+
+		assert.True(errors.Is(err, ErrTokenTooLarge))
+	})
+}
@@ -0,0 +1,221 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Severity represents the severity of a lint Finding.
+type Severity int
+
+const (
+	// SeverityInfo represents a finding worth knowing.
+	SeverityInfo Severity = iota
+	// SeverityWarning represents a finding which weakens the security of the
+	// token.
+	SeverityWarning
+	// SeverityError represents a finding which should not occur in production.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// Finding represents an issue reported by Lint.
+type Finding struct {
+	Severity Severity
+	// Code is a short and stable identifier of the issue, like "missing-exp".
+	Code    string
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Code, f.Message)
+}
+
+// LintOption represents the options of Lint.
+type LintOption struct {
+	// MaxLifetime is the longest acceptable lifetime of a token, defaults to
+	// 24 hours.
+	MaxLifetime time.Duration
+	// MaxTokenSize is the largest acceptable size of a token in bytes,
+	// defaults to 8192.
+	MaxTokenSize int
+	// MinRSAKeyBits is the smallest acceptable size of RSA keys, defaults to
+	// 2048.
+	MinRSAKeyBits int
+}
+
+var sensitiveClaimNames = []string{
+	"password", "passwd", "pwd", "secret", "ssn", "credit_card", "creditcard",
+	"card_number", "cardnumber", "cvv", "api_key", "apikey", "private_key",
+}
+
+var hmacMinSecretSize = map[Algorithm]int{HS256: 32, HS384: 48, HS512: 64}
+
+// Lint inspects the given token, and the secret or key if it is not nil, and
+// reports the practices which weaken its security, like missing or long
+// "exp", missing "aud" or "iss", alg "none", the legacy non-base64url
+// encoding, oversize tokens, sensitive-looking claim names, weak HMAC secrets
+// and short RSA keys. The signature of the token is not verified.
+func Lint(token []byte, key interface{}, opt *LintOption) (findings []Finding) {
+	var o LintOption
+
+	if opt != nil {
+		o = *opt
+	}
+
+	if o.MaxLifetime == 0 {
+		o.MaxLifetime = 24 * time.Hour
+	}
+
+	if o.MaxTokenSize == 0 {
+		o.MaxTokenSize = 8192
+	}
+
+	if o.MinRSAKeyBits == 0 {
+		o.MinRSAKeyBits = 2048
+	}
+
+	report := func(severity Severity, code, format string, a ...interface{}) {
+		findings = append(findings, Finding{severity, code, fmt.Sprintf(format, a...)})
+	}
+
+	if len(token) > o.MaxTokenSize {
+		report(SeverityWarning, "oversize-token", "token is %d bytes, larger than %d", len(token), o.MaxTokenSize)
+	}
+
+	segments := bytes.Split(token, periodBytes)
+
+	if len(segments) != 3 {
+		report(SeverityError, "malformed", "token has %d segments instead of 3", len(segments))
+		return
+	}
+
+	if bytes.ContainsAny(token, "+/=") {
+		report(SeverityWarning, "non-base64url", "token is encoded with base64 instead of unpadded base64url")
+	}
+
+	var header Header
+	var payload Payload
+
+	if err := lintDecodeSegment(segments[0], &header); err != nil {
+		report(SeverityError, "malformed", "header can not be decoded: %v", err)
+		return
+	}
+
+	if err := lintDecodeSegment(segments[1], &payload); err != nil {
+		report(SeverityError, "malformed", "payload can not be decoded: %v", err)
+		return
+	}
+
+	alg, _ := header["alg"].(string)
+
+	if strings.EqualFold(alg, "none") {
+		report(SeverityError, "alg-none", "token is not signed")
+	} else if _, ok := algImpMap[Algorithm(alg)]; !ok {
+		report(SeverityWarning, "unsupported-alg", "algorithm %q is not supported", alg)
+	}
+
+	if exp, ok := payload["exp"].(float64); !ok {
+		report(SeverityError, "missing-exp", "token never expires")
+	} else if lifetime, ok := lintLifetime(header, payload, exp); ok && lifetime > o.MaxLifetime {
+		report(SeverityWarning, "long-lifetime", "token lifetime %s is longer than %s", lifetime, o.MaxLifetime)
+	}
+
+	if _, ok := payload["aud"]; !ok {
+		report(SeverityWarning, "missing-aud", "token is not restricted to an audience")
+	}
+
+	if _, ok := payload["iss"]; !ok {
+		report(SeverityWarning, "missing-iss", "token has no issuer")
+	}
+
+	for name := range payload {
+		lower := strings.ToLower(name)
+
+		for _, sensitive := range sensitiveClaimNames {
+			if strings.Contains(lower, sensitive) {
+				report(SeverityWarning, "sensitive-claim", "claim %q looks sensitive, token payloads are not encrypted", name)
+				break
+			}
+		}
+	}
+
+	switch k := key.(type) {
+	case string:
+		lintHMACSecret(Algorithm(alg), len(k), report)
+	case []byte:
+		lintHMACSecret(Algorithm(alg), len(k), report)
+	case *rsa.PrivateKey:
+		lintRSAKey(k.N.BitLen(), o.MinRSAKeyBits, report)
+	case *rsa.PublicKey:
+		lintRSAKey(k.N.BitLen(), o.MinRSAKeyBits, report)
+	}
+
+	return
+}
+
+func lintHMACSecret(alg Algorithm, size int, report func(Severity, string, string, ...interface{})) {
+	if min, ok := hmacMinSecretSize[alg]; ok && size < min {
+		report(SeverityError, "weak-secret", "HMAC secret is %d bytes, %s requires at least %d", size, alg, min)
+	}
+}
+
+func lintRSAKey(bits, min int, report func(Severity, string, string, ...interface{})) {
+	if bits < min {
+		report(SeverityError, "short-rsa-key", "RSA key is %d bits, shorter than %d", bits, min)
+	}
+}
+
+// lintLifetime returns the lifetime of the token. "exp" is the lifetime Sign
+// produces, unless the token is an access token or "exp" is later than
+// "iat", in which case it is the absolute time of RFC 7519 and the lifetime
+// is only known when "iat" is present.
+func lintLifetime(header Header, payload Payload, exp float64) (time.Duration, bool) {
+	iat, hasIat := claimNumber(payload["iat"])
+	typ, _ := header["typ"].(string)
+
+	if normalizeType(typ) == AccessTokenType || (hasIat && exp > iat) {
+		if !hasIat {
+			return 0, false
+		}
+
+		exp -= iat
+	}
+
+	return time.Duration(exp) * time.Second, true
+}
+
+// lintDecodeSegment decodes segments in any of the base64 variants, since
+// the encoding is reported separately.
+func lintDecodeSegment(segment []byte, v interface{}) (err error) {
+	var b []byte
+
+	for _, encoding := range []*base64.Encoding{
+		base64.RawURLEncoding, base64.URLEncoding, base64.StdEncoding, base64.RawStdEncoding,
+	} {
+		if b, err = encoding.DecodeString(string(segment)); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return
+	}
+
+	return json.Unmarshal(b, v)
+}
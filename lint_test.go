@@ -0,0 +1,121 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lintCodes(findings []Finding) []string {
+	codes := []string{}
+
+	for _, f := range findings {
+		codes = append(codes, f.Code)
+	}
+
+	return codes
+}
+
+func TestLint(t *testing.T) {
+	assert := assert.New(t)
+
+	secret := strings.Repeat("k", 32)
+
+	t.Run("Should report nothing but encoding for a good token", func(t *testing.T) {
+		token, err := Sign(Payload{"foo": "bar"}, secret, &SignOption{
+			Issuer:    "testIssuer",
+			Audience:  "testAudience",
+			ExpiresIn: time.Hour,
+		})
+
+		assert.Nil(err)
+
+		findings := Lint(token, secret, nil)
+
+		for _, f := range findings {
+			assert.Equal("non-base64url", f.Code)
+			assert.Equal(SeverityWarning, f.Severity)
+		}
+	})
+
+	t.Run("Should report missing claims, sensitive claims and weak secret", func(t *testing.T) {
+		token, err := Sign(Payload{"Password": "123"}, "key", nil)
+
+		assert.Nil(err)
+
+		codes := lintCodes(Lint(token, "key", nil))
+
+		for _, code := range []string{"missing-exp", "missing-aud", "missing-iss", "sensitive-claim", "weak-secret"} {
+			assert.Contains(codes, code)
+		}
+	})
+
+	t.Run("Should report long lifetime", func(t *testing.T) {
+		token, err := Sign(Payload{}, secret, &SignOption{ExpiresIn: 72 * time.Hour})
+
+		assert.Nil(err)
+		assert.Contains(lintCodes(Lint(token, nil, nil)), "long-lifetime")
+		assert.NotContains(lintCodes(Lint(token, nil, &LintOption{MaxLifetime: 100 * time.Hour})), "long-lifetime")
+	})
+
+	t.Run("Should lint lifetime of absolute exp", func(t *testing.T) {
+		opt := &AccessTokenOption{
+			Issuer:    "iss",
+			Audience:  []string{"aud"},
+			Subject:   "sub",
+			ClientID:  "client",
+			ExpiresIn: time.Hour,
+		}
+
+		token, err := SignAccessToken(nil, secret, opt)
+
+		assert.Nil(err)
+		assert.NotContains(lintCodes(Lint(token, nil, nil)), "long-lifetime")
+
+		opt.ExpiresIn = 72 * time.Hour
+
+		token, err = SignAccessToken(nil, secret, opt)
+
+		assert.Nil(err)
+		assert.Contains(lintCodes(Lint(token, nil, nil)), "long-lifetime")
+
+		now := time.Now().Unix()
+		token = rawURLToken(t, Header{"alg": "HS256", "typ": "JWT"}, Payload{"iat": now, "exp": now + 600}, secret)
+
+		assert.NotContains(lintCodes(Lint(token, nil, nil)), "long-lifetime")
+	})
+
+	t.Run("Should report alg none and oversize token", func(t *testing.T) {
+		enc := base64.RawURLEncoding
+		token := enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+			enc.EncodeToString([]byte(`{"exp":60,"iss":"a","aud":"b","data":"`+strings.Repeat("x", 10000)+`"}`)) + "."
+
+		findings := Lint([]byte(token), nil, nil)
+
+		assert.Equal([]string{"oversize-token", "alg-none"}, lintCodes(findings))
+		assert.Equal(SeverityError, findings[1].Severity)
+		assert.Equal("error: alg-none: token is not signed", findings[1].String())
+	})
+
+	t.Run("Should report malformed token", func(t *testing.T) {
+		assert.Equal([]string{"malformed"}, lintCodes(Lint([]byte("a.b"), nil, nil)))
+		assert.Equal([]string{"malformed"}, lintCodes(Lint([]byte("a.b.c"), nil, nil)))
+	})
+
+	t.Run("Should report short RSA key", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+		assert.Nil(err)
+
+		token, err := Sign(Payload{}, key, &SignOption{Algorithm: RS256})
+
+		assert.Nil(err)
+		assert.Contains(lintCodes(Lint(token, key, nil)), "short-rsa-key")
+		assert.Contains(lintCodes(Lint(token, &key.PublicKey, nil)), "short-rsa-key")
+	})
+}
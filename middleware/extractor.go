@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrInvalidAuthorization is returned when the Authorization header uses the
+// Bearer scheme but is malformed, or when the token in the WebSocket
+// subprotocol is not base64url.
+var ErrInvalidAuthorization = errors.New("jwt: invalid authorization header")
+
+// ErrInvalidRequest is returned when the request carrying the token can not
+// be parsed.
+var ErrInvalidRequest = errors.New("jwt: invalid request")
+
+// Extractor extracts the token from a request.
+type Extractor interface {
+	// Extract returns the token found in r, or ErrTokenMissing if there is
+	// none.
+	Extract(r *http.Request) (string, error)
+}
+
+// ExtractorFunc is an adapter to allow the use of ordinary functions as
+// Extractor.
+type ExtractorFunc func(r *http.Request) (string, error)
+
+// Extract implements Extractor.
+func (f ExtractorFunc) Extract(r *http.Request) (string, error) {
+	return f(r)
+}
+
+// FromAuthorizationHeader returns an Extractor which extracts the token from
+// the "Authorization: Bearer" header, as described in RFC 6750 section 2.1.
+// Headers using another scheme are treated as missing, while malformed Bearer
+// headers, like extra whitespaces or multiple Authorization headers, result in
+// ErrInvalidAuthorization.
+func FromAuthorizationHeader() Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		values := r.Header["Authorization"]
+
+		if len(values) == 0 {
+			return "", ErrTokenMissing
+		}
+
+		if len(values) > 1 {
+			return "", ErrInvalidAuthorization
+		}
+
+		fields := strings.SplitN(values[0], " ", 2)
+
+		if !strings.EqualFold(fields[0], "Bearer") {
+			return "", ErrTokenMissing
+		}
+
+		if len(fields) != 2 || !isBearerToken(fields[1]) {
+			return "", ErrInvalidAuthorization
+		}
+
+		return fields[1], nil
+	})
+}
+
+// isBearerToken reports whether s is made of the b64token characters of
+// RFC 6750. Padding is accepted anywhere since each segment of the tokens
+// produced by jwt.Sign may be padded.
+func isBearerToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			strings.IndexByte("-._~+/=", c) >= 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isBase64URLToken reports whether s is made of the characters of the
+// segments and separators of tokens encoded with unpadded base64url.
+func isBase64URLToken(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			strings.IndexByte("-._", c) >= 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FromHeader returns an Extractor which extracts the token from the value of
+// the given header.
+func FromHeader(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		return nonEmpty(strings.TrimSpace(r.Header.Get(name)))
+	})
+}
+
+// FromCookie returns an Extractor which extracts the token from the cookie
+// with the given name.
+func FromCookie(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		cookie, err := r.Cookie(name)
+
+		if err != nil {
+			return "", ErrTokenMissing
+		}
+
+		return nonEmpty(cookie.Value)
+	})
+}
+
+// FromQuery returns an Extractor which extracts the token from the URL query
+// parameter with the given name, like "access_token".
+func FromQuery(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		return nonEmpty(r.URL.Query().Get(name))
+	})
+}
+
+// FromForm returns an Extractor which extracts the token from the
+// application/x-www-form-urlencoded body parameter with the given name.
+func FromForm(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		if err := r.ParseForm(); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+
+		return nonEmpty(r.PostForm.Get(name))
+	})
+}
+
+// FromWebSocketProtocol returns an Extractor which extracts the token from the
+// Sec-WebSocket-Protocol header, for browsers which can not set headers on
+// WebSocket connections. The token is the remainder of the first subprotocol
+// starting with prefix, the server should echo that subprotocol back when
+// accepting the connection. Since subprotocols must be RFC 7230 tokens, which
+// can not contain "/" or "=", the token must be signed with the
+// RawURLEncoding option of jwt.SignOption, other tokens result in
+// ErrInvalidAuthorization.
+func FromWebSocketProtocol(prefix string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		for _, value := range r.Header["Sec-Websocket-Protocol"] {
+			for _, protocol := range strings.Split(value, ",") {
+				protocol = strings.TrimSpace(protocol)
+
+				if !strings.HasPrefix(protocol, prefix) {
+					continue
+				}
+
+				token := protocol[len(prefix):]
+
+				if token != "" && !isBase64URLToken(token) {
+					return "", ErrInvalidAuthorization
+				}
+
+				return nonEmpty(token)
+			}
+		}
+
+		return "", ErrTokenMissing
+	})
+}
+
+// Chain returns an Extractor which tries the given extractors in order and
+// returns the first token found. Errors other than ErrTokenMissing stop the
+// chain.
+func Chain(extractors ...Extractor) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		for _, e := range extractors {
+			token, err := e.Extract(r)
+
+			if err != ErrTokenMissing {
+				return token, err
+			}
+		}
+
+		return "", ErrTokenMissing
+	})
+}
+
+func nonEmpty(token string) (string, error) {
+	if token == "" {
+		return "", ErrTokenMissing
+	}
+
+	return token, nil
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DavidCai1993/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractor(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Should extract token from Authorization header", func(t *testing.T) {
+		e := FromAuthorizationHeader()
+
+		for auth, expected := range map[string]string{
+			"Bearer abc.def-ghi_jkl~+/==": "abc.def-ghi_jkl~+/==",
+			"bearer abc":                  "abc",
+		} {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", auth)
+
+			token, err := e.Extract(r)
+
+			assert.Nil(err)
+			assert.Equal(expected, token)
+		}
+	})
+
+	t.Run("Should return ErrInvalidAuthorization when Bearer is malformed", func(t *testing.T) {
+		e := FromAuthorizationHeader()
+
+		for _, auth := range []string{"Bearer", "Bearer ", "Bearer  abc", "Bearer a,b", "Bearer a b"} {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", auth)
+
+			_, err := e.Extract(r)
+
+			assert.Equal(ErrInvalidAuthorization, err, auth)
+		}
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Add("Authorization", "Bearer abc")
+		r.Header.Add("Authorization", "Bearer def")
+
+		_, err := e.Extract(r)
+
+		assert.Equal(ErrInvalidAuthorization, err)
+	})
+
+	t.Run("Should return ErrTokenMissing when scheme is not Bearer", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Basic abc")
+
+		_, err := FromAuthorizationHeader().Extract(r)
+
+		assert.Equal(ErrTokenMissing, err)
+	})
+
+	t.Run("Should extract token from header, cookie, query and form", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/?access_token=fromQuery", strings.NewReader("access_token=fromForm"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("X-Token", "fromHeader")
+		r.AddCookie(&http.Cookie{Name: "token", Value: "fromCookie"})
+
+		for _, c := range []struct {
+			extractor Extractor
+			expected  string
+		}{
+			{FromHeader("X-Token"), "fromHeader"},
+			{FromCookie("token"), "fromCookie"},
+			{FromQuery("access_token"), "fromQuery"},
+			{FromForm("access_token"), "fromForm"},
+		} {
+			token, err := c.extractor.Extract(r)
+
+			assert.Nil(err)
+			assert.Equal(c.expected, token)
+		}
+
+		for _, e := range []Extractor{FromHeader("X-Other"), FromCookie("other"), FromQuery("other"), FromForm("other")} {
+			_, err := e.Extract(r)
+
+			assert.Equal(ErrTokenMissing, err)
+		}
+	})
+
+	t.Run("Should return ErrInvalidRequest when form is malformed", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/", strings.NewReader("access_token=%zz"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		_, err := FromForm("access_token").Extract(r)
+
+		assert.True(errors.Is(err, ErrInvalidRequest))
+	})
+
+	t.Run("Should extract token from WebSocket subprotocol", func(t *testing.T) {
+		signed, err := jwt.Sign(jwt.Payload{"foo": "~~~>>>???"}, "key", &jwt.SignOption{
+			ExpiresIn:      time.Minute,
+			RawURLEncoding: true,
+		})
+
+		assert.Nil(err)
+
+		// Subprotocols are RFC 7230 tokens.
+		tchar := regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
+		assert.Regexp(tchar, "access_token."+string(signed))
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Sec-WebSocket-Protocol", "chat, access_token."+string(signed))
+
+		token, err := FromWebSocketProtocol("access_token.").Extract(r)
+
+		assert.Nil(err)
+		assert.Equal(string(signed), token)
+
+		_, payload, err := jwt.Verify([]byte(token), "key", nil)
+
+		assert.Nil(err)
+		assert.Equal("~~~>>>???", payload["foo"])
+
+		_, err = FromWebSocketProtocol("other.").Extract(r)
+
+		assert.Equal(ErrTokenMissing, err)
+
+		padded, err := jwt.Sign(jwt.Payload{"foo": "~~~>>>???"}, "key", &jwt.SignOption{ExpiresIn: time.Minute})
+
+		assert.Nil(err)
+
+		r.Header.Set("Sec-WebSocket-Protocol", "chat, access_token."+string(padded))
+
+		_, err = FromWebSocketProtocol("access_token.").Extract(r)
+
+		assert.Equal(ErrInvalidAuthorization, err)
+	})
+
+	t.Run("Should try extractors in order", func(t *testing.T) {
+		e := Chain(FromAuthorizationHeader(), FromCookie("token"), FromQuery("access_token"))
+
+		r := httptest.NewRequest("GET", "/?access_token=fromQuery", nil)
+		r.AddCookie(&http.Cookie{Name: "token", Value: "fromCookie"})
+
+		token, err := e.Extract(r)
+
+		assert.Nil(err)
+		assert.Equal("fromCookie", token)
+
+		r.Header.Set("Authorization", "Bearer ")
+
+		_, err = e.Extract(r)
+
+		assert.Equal(ErrInvalidAuthorization, err)
+
+		_, err = e.Extract(httptest.NewRequest("GET", "/", nil))
+
+		assert.Equal(ErrTokenMissing, err)
+	})
+}
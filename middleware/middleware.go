@@ -0,0 +1,179 @@
+// Package middleware provides net/http middleware which authenticates
+// requests with JSON web tokens.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/DavidCai1993/jwt"
+)
+
+// ErrTokenMissing is returned when no token is found in the request.
+var ErrTokenMissing = errors.New("jwt: token missing")
+
+// ErrKeyUnavailable is matched by the errors returned when the KeyFunc
+// failed, which also match the error of the KeyFunc.
+var ErrKeyUnavailable = errors.New("jwt: key unavailable")
+
+type keyError struct {
+	err error
+}
+
+func (e *keyError) Error() string {
+	return ErrKeyUnavailable.Error() + ": " + e.err.Error()
+}
+
+func (e *keyError) Unwrap() error {
+	return e.err
+}
+
+func (e *keyError) Is(target error) bool {
+	return target == ErrKeyUnavailable
+}
+
+type contextKey int
+
+const (
+	headerKey contextKey = iota
+	payloadKey
+)
+
+// KeyFunc returns the secret or key used to verify the token of the request.
+type KeyFunc func(r *http.Request) (interface{}, error)
+
+// StaticKey returns a KeyFunc which always returns the given key.
+func StaticKey(key interface{}) KeyFunc {
+	return func(*http.Request) (interface{}, error) {
+		return key, nil
+	}
+}
+
+// VerifyFunc verifies the token with the given key and option, such as
+// jwt.VerifyContext and jwt.VerifyAccessTokenContext.
+type VerifyFunc func(ctx context.Context, token []byte, key interface{}, opt *jwt.VerifyOption) (jwt.Header, jwt.Payload, error)
+
+// ErrorHandler handles the error occurred when authenticating a request.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// Option represents the options of Middleware.
+type Option struct {
+	// VerifyOption is the option passed to jwt.VerifyContext along with the
+	// context of the request, it is copied for every request.
+	VerifyOption *jwt.VerifyOption
+	// Verify verifies the token, jwt.VerifyContext will be used if it is nil.
+	// Use jwt.VerifyAccessTokenContext to accept only RFC 9068 access tokens.
+	Verify VerifyFunc
+	// Extractor extracts the token from requests, FromAuthorizationHeader()
+	// will be used if it is nil.
+	Extractor Extractor
+	// ErrorHandler is called when the token is missing on a required route or
+	// is invalid. Responder{}.Respond will be used if it is nil.
+	ErrorHandler ErrorHandler
+}
+
+// Middleware verifies the token of requests and stores the verified header
+// and payload in the request context.
+type Middleware struct {
+	keyFunc KeyFunc
+	opt     Option
+}
+
+// New returns a Middleware which verifies tokens with the key returned by
+// keyFunc.
+func New(keyFunc KeyFunc, opt *Option) *Middleware {
+	m := &Middleware{keyFunc: keyFunc}
+
+	if opt != nil {
+		m.opt = *opt
+	}
+
+	if m.opt.Extractor == nil {
+		m.opt.Extractor = FromAuthorizationHeader()
+	}
+
+	if m.opt.ErrorHandler == nil {
+		m.opt.ErrorHandler = Responder{}.Respond
+	}
+
+	if m.opt.Verify == nil {
+		m.opt.Verify = jwt.VerifyContext
+	}
+
+	return m
+}
+
+// Required wraps next so that requests without a valid token are rejected.
+func (m *Middleware) Required(next http.Handler) http.Handler {
+	return m.handler(next, true)
+}
+
+// Optional wraps next so that requests without a token are passed through
+// without claims, while requests with an invalid token are still rejected.
+func (m *Middleware) Optional(next http.Handler) http.Handler {
+	return m.handler(next, false)
+}
+
+func (m *Middleware) handler(next http.Handler, required bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := m.opt.Extractor.Extract(r)
+
+		if err == ErrTokenMissing && !required {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err != nil {
+			m.opt.ErrorHandler(w, r, err)
+			return
+		}
+
+		header, payload, err := m.verify(r, []byte(token))
+
+		if err != nil {
+			m.opt.ErrorHandler(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), header, payload)))
+	})
+}
+
+func (m *Middleware) verify(r *http.Request, token []byte) (jwt.Header, jwt.Payload, error) {
+	key, err := m.keyFunc(r)
+
+	if err != nil {
+		return nil, nil, &keyError{err}
+	}
+
+	var opt jwt.VerifyOption
+
+	if m.opt.VerifyOption != nil {
+		opt = *m.opt.VerifyOption
+	}
+
+	return m.opt.Verify(r.Context(), token, key, &opt)
+}
+
+// NewContext returns a copy of ctx which carries the given verified header
+// and payload.
+func NewContext(ctx context.Context, header jwt.Header, payload jwt.Payload) context.Context {
+	ctx = context.WithValue(ctx, headerKey, header)
+
+	return context.WithValue(ctx, payloadKey, payload)
+}
+
+// HeaderFromContext returns the verified header stored in ctx.
+func HeaderFromContext(ctx context.Context) (jwt.Header, bool) {
+	header, ok := ctx.Value(headerKey).(jwt.Header)
+
+	return header, ok
+}
+
+// PayloadFromContext returns the verified payload stored in ctx.
+func PayloadFromContext(ctx context.Context) (jwt.Payload, bool) {
+	payload, ok := ctx.Value(payloadKey).(jwt.Payload)
+
+	return payload, ok
+}
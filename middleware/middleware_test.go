@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DavidCai1993/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	token, err := jwt.Sign(jwt.Payload{"foo": "bar"}, "key", &jwt.SignOption{
+		Issuer:    "testIssuer",
+		ExpiresIn: time.Minute,
+	})
+
+	assert.Nil(err)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if payload, ok := PayloadFromContext(r.Context()); ok {
+			header, _ := HeaderFromContext(r.Context())
+			w.Write([]byte(payload["foo"].(string) + " " + header["typ"].(string)))
+			return
+		}
+
+		w.Write([]byte("anonymous"))
+	})
+
+	serve := func(h http.Handler, auth string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/", nil)
+
+		if auth != "" {
+			r.Header.Set("Authorization", auth)
+		}
+
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, r)
+
+		return w
+	}
+
+	m := New(StaticKey("key"), &Option{
+		VerifyOption: &jwt.VerifyOption{Issuer: "testIssuer"},
+	})
+
+	t.Run("Should store claims in context when token is valid", func(t *testing.T) {
+		w := serve(m.Required(handler), "Bearer "+string(token))
+
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("bar JWT", w.Body.String())
+	})
+
+	t.Run("Should reject required route when token is missing", func(t *testing.T) {
+		w := serve(m.Required(handler), "")
+
+		assert.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Should pass optional route when token is missing", func(t *testing.T) {
+		w := serve(m.Optional(handler), "")
+
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("anonymous", w.Body.String())
+	})
+
+	t.Run("Should reject optional route when token is invalid", func(t *testing.T) {
+		w := serve(m.Optional(handler), "Bearer "+string(token)+"x")
+
+		assert.Equal(http.StatusUnauthorized, w.Code)
+		assert.Contains(w.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+	})
+
+	t.Run("Should respond 400 when Authorization header is malformed", func(t *testing.T) {
+		w := serve(m.Optional(handler), "Bearer  "+string(token))
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Should reject when reserved claim is invalid", func(t *testing.T) {
+		m := New(StaticKey("key"), &Option{
+			VerifyOption: &jwt.VerifyOption{Issuer: "otherIssuer"},
+		})
+
+		w := serve(m.Required(handler), "Bearer "+string(token))
+
+		assert.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Should pass request context to validators", func(t *testing.T) {
+		var received context.Context
+
+		m := New(StaticKey("key"), &Option{
+			VerifyOption: &jwt.VerifyOption{Validators: []jwt.Validator{
+				func(ctx context.Context, header jwt.Header, payload jwt.Payload) error {
+					received = ctx
+
+					return nil
+				},
+			}},
+		})
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+string(token))
+
+		m.Required(handler).ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.Equal(r.Context(), received)
+	})
+
+	t.Run("Should use the given Extractor", func(t *testing.T) {
+		m := New(StaticKey("key"), &Option{Extractor: FromQuery("access_token")})
+
+		r := httptest.NewRequest("GET", "/?access_token="+url.QueryEscape(string(token)), nil)
+		w := httptest.NewRecorder()
+
+		m.Required(handler).ServeHTTP(w, r)
+
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("bar JWT", w.Body.String())
+	})
+
+	t.Run("Should verify with the given Verify", func(t *testing.T) {
+		accessOpt := &jwt.AccessTokenOption{
+			Issuer:    "testIssuer",
+			Audience:  []string{"testAudience"},
+			Subject:   "alice",
+			ClientID:  "client",
+			ExpiresIn: time.Minute,
+		}
+
+		accessToken, err := jwt.SignAccessToken(jwt.Payload{"foo": "bar"}, "key", accessOpt)
+
+		assert.Nil(err)
+
+		accessOpt.Clock = func() time.Time { return time.Now().Add(-2 * time.Minute) }
+
+		expired, err := jwt.SignAccessToken(jwt.Payload{"foo": "bar"}, "key", accessOpt)
+
+		assert.Nil(err)
+
+		m := New(StaticKey("key"), &Option{
+			VerifyOption: &jwt.VerifyOption{Issuer: "testIssuer", Audience: "testAudience"},
+			Verify:       jwt.VerifyAccessTokenContext,
+		})
+
+		w := serve(m.Required(handler), "Bearer "+string(accessToken))
+
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("bar at+jwt", w.Body.String())
+
+		assert.Equal(http.StatusUnauthorized, serve(m.Required(handler), "Bearer "+string(expired)).Code)
+		assert.Equal(http.StatusUnauthorized, serve(m.Required(handler), "Bearer "+string(token)).Code)
+	})
+
+	t.Run("Should call ErrorHandler with the error", func(t *testing.T) {
+		var received []error
+
+		keyErr := errors.New("no key")
+
+		m := New(func(*http.Request) (interface{}, error) {
+			return nil, keyErr
+		}, &Option{
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				received = append(received, err)
+				w.WriteHeader(http.StatusTeapot)
+			},
+		})
+
+		assert.Equal(http.StatusTeapot, serve(m.Required(handler), "").Code)
+		assert.Equal(http.StatusTeapot, serve(m.Required(handler), "Bearer "+string(token)).Code)
+		assert.Equal(2, len(received))
+		assert.Equal(ErrTokenMissing, received[0])
+		assert.True(errors.Is(received[1], keyErr))
+		assert.True(errors.Is(received[1], ErrKeyUnavailable))
+
+		m = New(func(*http.Request) (interface{}, error) {
+			return nil, keyErr
+		}, nil)
+
+		assert.Equal(http.StatusServiceUnavailable, serve(m.Required(handler), "Bearer "+string(token)).Code)
+	})
+}
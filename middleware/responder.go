@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/DavidCai1993/jwt"
+)
+
+// ErrInsufficientScope is returned when the token does not grant the scope
+// required by the request.
+var ErrInsufficientScope = errors.New("jwt: insufficient scope")
+
+type bearerError struct {
+	status      int
+	code        string
+	description string
+}
+
+// bearerErrors are matched in order with errors.Is, so that the sentinel
+// errors wrapped with details are recognized.
+var bearerErrors = []struct {
+	err error
+	bearerError
+}{
+	{ErrTokenMissing, bearerError{http.StatusUnauthorized, "", ""}},
+	{ErrInvalidAuthorization, bearerError{http.StatusBadRequest, "invalid_request", "The authorization header is malformed"}},
+	{ErrInvalidRequest, bearerError{http.StatusBadRequest, "invalid_request", "The request is malformed"}},
+	{jwt.ErrTokenTooLarge, bearerError{http.StatusBadRequest, "invalid_request", "The access token is too large"}},
+	{ErrKeyUnavailable, bearerError{http.StatusServiceUnavailable, "", ""}},
+	{ErrInsufficientScope, bearerError{http.StatusForbidden, "insufficient_scope", "The access token does not grant the required scope"}},
+	{jwt.ErrTokenExpired, bearerError{http.StatusUnauthorized, "invalid_token", "The access token expired"}},
+	{jwt.ErrInvalidSignature, bearerError{http.StatusUnauthorized, "invalid_token", "The access token signature is invalid"}},
+	{jwt.ErrInvalidReservedClaim, bearerError{http.StatusUnauthorized, "invalid_token", "The access token audience, issuer or subject is invalid"}},
+	{jwt.ErrInvalidHeaderType, bearerError{http.StatusUnauthorized, "invalid_token", "The access token type is invalid"}},
+	{jwt.ErrInvalidAlgorithm, bearerError{http.StatusUnauthorized, "invalid_token", "The access token algorithm is not supported"}},
+	{jwt.ErrInvalidToken, bearerError{http.StatusUnauthorized, "invalid_token", "The access token is malformed"}},
+	{jwt.ErrTokenTooOld, bearerError{http.StatusUnauthorized, "invalid_token", "The access token expired"}},
+	{jwt.ErrTokenIssuedInFuture, bearerError{http.StatusUnauthorized, "invalid_token", "The access token is not yet valid"}},
+	{jwt.ErrPayloadMissingIat, bearerError{http.StatusUnauthorized, "invalid_token", "The access token is missing iat"}},
+	{jwt.ErrPayloadMissingExp, bearerError{http.StatusUnauthorized, "invalid_token", "The access token is missing exp"}},
+	{jwt.ErrTokenRevoked, bearerError{http.StatusUnauthorized, "invalid_token", "The access token is revoked"}},
+	{jwt.ErrTokenIssuedBeforeCutoff, bearerError{http.StatusUnauthorized, "invalid_token", "The access token is revoked"}},
+}
+
+var defaultBearerError = bearerError{http.StatusUnauthorized, "invalid_token", "The access token is invalid"}
+
+// Responder writes error responses with the WWW-Authenticate header described
+// in RFC 6750 section 3. Its Respond method can be used as the ErrorHandler of
+// Middleware, or called from any http.Handler.
+type Responder struct {
+	// Realm is the protection space included in the challenge if it is not
+	// empty.
+	Realm string
+	// Scope is the space-delimited scopes required, included in the challenge
+	// if it is not empty.
+	Scope string
+}
+
+// Respond writes the challenge and status corresponding to err: 400 with
+// "invalid_request" for malformed requests, 401 with "invalid_token" for
+// invalid tokens, 403 with "insufficient_scope" for ErrInsufficientScope,
+// and 401 without error code when the token is missing. Failures of the key
+// source result in 503 without challenge, since they are not the fault of
+// the client. The descriptions of unknown errors are not disclosed.
+func (rs Responder) Respond(w http.ResponseWriter, r *http.Request, err error) {
+	be := defaultBearerError
+
+	for _, e := range bearerErrors {
+		if errors.Is(err, e.err) {
+			be = e.bearerError
+			break
+		}
+	}
+
+	if be.status >= http.StatusInternalServerError {
+		http.Error(w, http.StatusText(be.status), be.status)
+		return
+	}
+
+	params := []string{}
+
+	if rs.Realm != "" {
+		params = append(params, authParam("realm", rs.Realm))
+	}
+
+	if be.code != "" {
+		params = append(params, authParam("error", be.code), authParam("error_description", be.description))
+	}
+
+	if rs.Scope != "" && (be.code == "" || be.code == "insufficient_scope") {
+		params = append(params, authParam("scope", rs.Scope))
+	}
+
+	challenge := "Bearer"
+
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+
+	http.Error(w, http.StatusText(be.status), be.status)
+}
+
+// authParam formats a quoted auth-param, dropping the characters which are
+// not allowed in its value.
+func authParam(name, value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return -1
+		}
+
+		return r
+	}, value)
+
+	return name + `="` + value + `"`
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DavidCai1993/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponder(t *testing.T) {
+	assert := assert.New(t)
+
+	respond := func(rs Responder, err error) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+
+		rs.Respond(w, httptest.NewRequest("GET", "/", nil), err)
+
+		return w
+	}
+
+	t.Run("Should respond 401 without error code when token is missing", func(t *testing.T) {
+		w := respond(Responder{Realm: "example"}, ErrTokenMissing)
+
+		assert.Equal(http.StatusUnauthorized, w.Code)
+		assert.Equal(`Bearer realm="example"`, w.Header().Get("WWW-Authenticate"))
+
+		w = respond(Responder{}, ErrTokenMissing)
+
+		assert.Equal("Bearer", w.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("Should respond 401 with invalid_token when token is invalid", func(t *testing.T) {
+		w := respond(Responder{Realm: "example"}, jwt.ErrTokenExpired)
+
+		assert.Equal(http.StatusUnauthorized, w.Code)
+		assert.Equal(`Bearer realm="example", error="invalid_token", error_description="The access token expired"`,
+			w.Header().Get("WWW-Authenticate"))
+
+		for _, err := range []error{jwt.ErrInvalidSignature, jwt.ErrInvalidReservedClaim, jwt.ErrTokenRevoked} {
+			w := respond(Responder{}, err)
+
+			assert.Equal(http.StatusUnauthorized, w.Code)
+			assert.Contains(w.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+		}
+	})
+
+	t.Run("Should not disclose unknown errors", func(t *testing.T) {
+		w := respond(Responder{}, errors.New(`secret "detail"`))
+
+		assert.Equal(http.StatusUnauthorized, w.Code)
+		assert.Equal(`Bearer error="invalid_token", error_description="The access token is invalid"`,
+			w.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("Should respond 400 with invalid_request when request is malformed", func(t *testing.T) {
+		for _, err := range []error{
+			ErrInvalidAuthorization,
+			fmt.Errorf("%w: invalid semicolon separator in query", ErrInvalidRequest),
+			fmt.Errorf("%w: token is longer than 8192 bytes", jwt.ErrTokenTooLarge),
+		} {
+			w := respond(Responder{}, err)
+
+			assert.Equal(http.StatusBadRequest, w.Code, err)
+			assert.Contains(w.Header().Get("WWW-Authenticate"), `error="invalid_request"`, err)
+		}
+	})
+
+	t.Run("Should match wrapped errors", func(t *testing.T) {
+		w := respond(Responder{}, fmt.Errorf("%w: typ is at+jwt", jwt.ErrInvalidHeaderType))
+
+		assert.Equal(http.StatusUnauthorized, w.Code)
+		assert.Contains(w.Header().Get("WWW-Authenticate"), "The access token type is invalid")
+	})
+
+	t.Run("Should respond 503 without challenge when key is unavailable", func(t *testing.T) {
+		w := respond(Responder{Realm: "example"}, &keyError{errors.New("jwks endpoint unreachable")})
+
+		assert.Equal(http.StatusServiceUnavailable, w.Code)
+		assert.Empty(w.Header().Get("WWW-Authenticate"))
+		assert.NotContains(w.Body.String(), "unreachable")
+	})
+
+	t.Run("Should respond 403 with insufficient_scope and scope", func(t *testing.T) {
+		w := respond(Responder{Realm: `ex"ample`, Scope: "read write"}, ErrInsufficientScope)
+
+		assert.Equal(http.StatusForbidden, w.Code)
+		assert.Equal(`Bearer realm="example", error="insufficient_scope", `+
+			`error_description="The access token does not grant the required scope", scope="read write"`,
+			w.Header().Get("WWW-Authenticate"))
+	})
+}
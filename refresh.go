@@ -0,0 +1,159 @@
+package jwt
+
+import (
+	"bytes"
+	"time"
+)
+
+// OriginalIssuedAtClaim is the claim in which Refresh records the issue time
+// of the first token of a session.
+const OriginalIssuedAtClaim = "orig_iat"
+
+// RefreshOption represents the options of Refresh.
+type RefreshOption struct {
+	Algorithm Algorithm
+	Issuer    string
+	Audience  string
+	Subject   string
+	// Types specifies the accepted values of the "typ" header as in
+	// VerifyOption, the "typ" header of the given token is preserved.
+	Types []string
+	// AllowMissingType specifies whether to accept tokens without "typ".
+	AllowMissingType bool
+	// ExpiresIn specifies the lifetime of the re-issued token, the lifetime of
+	// the given token will be reused if it is zero.
+	ExpiresIn time.Duration
+	// GracePeriod specifies how long after its expiration a token can still
+	// be refreshed.
+	GracePeriod time.Duration
+	// MaxLifetime specifies the absolute maximum lifetime of a session counted
+	// from the original issue time, zero means no limit.
+	MaxLifetime time.Duration
+	// Revoker specifies the denylist of revoked tokens to consult, revoked
+	// tokens can not be refreshed. Tokens should be revoked with the same
+	// GracePeriod in RevokeOption, otherwise their entries expire before the
+	// grace period passes.
+	Revoker Revoker
+	// SubjectCutoff specifies the per-subject cutoffs to consult, tokens
+	// issued before the cutoff of their subject can not be refreshed.
+	SubjectCutoff SubjectCutoff
+	// Clock returns the current time, time.Now will be used if it is nil.
+	Clock func() time.Time
+}
+
+// Refresh verifies the given token and re-issues it with new "iat", "exp"
+// and "jti" if present, while preserving the other claims, the header and
+// the encoding of the token. A token which has expired
+// can still be refreshed within the grace period. The issue time of the first
+// token is recorded in the "orig_iat" claim, once the maximum lifetime counted
+// from it is exceeded, ErrMaxLifetimeExceeded will be returned.
+func Refresh(token []byte, secretOrPrivateKey interface{}, opt *RefreshOption) (refreshed []byte, err error) {
+	var (
+		header  Header
+		payload Payload
+		exp     time.Time
+		origIat time.Time
+	)
+
+	if opt == nil {
+		opt = &RefreshOption{}
+	}
+
+	if header, payload, err = Verify(token, secretOrPrivateKey, &VerifyOption{
+		Algorithm:        opt.Algorithm,
+		Issuer:           opt.Issuer,
+		Audience:         opt.Audience,
+		Subject:          opt.Subject,
+		Types:            opt.Types,
+		AllowMissingType: opt.AllowMissingType,
+		IngoreExpiration: true,
+		Revoker:          opt.Revoker,
+		SubjectCutoff:    opt.SubjectCutoff,
+		Clock:            opt.Clock,
+	}); err != nil {
+		return
+	}
+
+	if exp, err = payload.expTime(); err != nil {
+		return
+	}
+
+	if origIat, err = payload.origIat(); err != nil {
+		return
+	}
+
+	now := currentTime(opt.Clock)
+
+	if opt.MaxLifetime > 0 && !now.Before(origIat.Add(opt.MaxLifetime)) {
+		return nil, ErrMaxLifetimeExceeded
+	}
+
+	if !now.Before(exp.Add(opt.GracePeriod)) {
+		return nil, ErrRefreshExpired
+	}
+
+	expiresIn := opt.ExpiresIn
+
+	if expiresIn == 0 {
+		seconds, _ := claimNumber(payload["exp"])
+		expiresIn = time.Duration(int64(seconds * 1e9))
+	}
+
+	if opt.MaxLifetime > 0 {
+		remaining := origIat.Add(opt.MaxLifetime).Sub(now).Truncate(time.Second)
+
+		if remaining <= 0 {
+			return nil, ErrMaxLifetimeExceeded
+		}
+
+		if expiresIn > remaining {
+			expiresIn = remaining
+		}
+	}
+
+	claims := Payload{OriginalIssuedAtClaim: origIat.Unix()}
+
+	for k, v := range payload {
+		if k != "iat" && k != "exp" && k != OriginalIssuedAtClaim {
+			claims[k] = v
+		}
+	}
+
+	// The refreshed token is a new token, which should not share the
+	// identity of the given one, such as in the denylist of Revoker.
+	if _, ok := payload["jti"]; ok {
+		if claims["jti"], err = randomID(); err != nil {
+			return
+		}
+	}
+
+	customHeader := Header{}
+
+	for k, v := range header {
+		if k != "alg" && k != "typ" {
+			customHeader[k] = v
+		}
+	}
+
+	typ, hasType := header["typ"].(string)
+
+	// Signatures of the supported algorithms are padded in standard base64,
+	// so tokens without "+", "/" and "=" are encoded with base64url.
+	return Sign(claims, secretOrPrivateKey, &SignOption{
+		Algorithm:      opt.Algorithm,
+		ExpiresIn:      expiresIn,
+		Type:           typ,
+		OmitType:       !hasType,
+		Header:         customHeader,
+		RawURLEncoding: !bytes.ContainsAny(token, "+/="),
+		Clock:          opt.Clock,
+	})
+}
+
+func (p Payload) origIat() (time.Time, error) {
+	if v, ok := claimNumber(p[OriginalIssuedAtClaim]); ok {
+		return time.Unix(int64(v), 0), nil
+	}
+
+	return p.iat()
+}
@@ -0,0 +1,213 @@
+package jwt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefresh(t *testing.T) {
+	assert := assert.New(t)
+
+	custom := map[string]interface{}{
+		"test1k": "test1v",
+		"test2k": float64(234),
+	}
+
+	clockAt := func(t time.Time) func() time.Time {
+		return func() time.Time { return t }
+	}
+
+	issuedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	sign := func(expiresIn time.Duration) []byte {
+		token, err := Sign(custom, "key", &SignOption{
+			Issuer:    "testIssuer",
+			ExpiresIn: expiresIn,
+			Header:    Header{"kid": "testKid"},
+			Clock:     clockAt(issuedAt),
+		})
+
+		assert.Nil(err)
+
+		return token
+	}
+
+	t.Run("Should re-issue token with new iat and preserved claims", func(t *testing.T) {
+		refreshed, err := Refresh(sign(time.Minute), "key", &RefreshOption{
+			Issuer:      "testIssuer",
+			GracePeriod: 2 * time.Hour,
+		})
+
+		assert.Nil(err)
+
+		header, payload, err := Verify(refreshed, "key", nil)
+
+		assert.Nil(err)
+		assert.Equal("testKid", header["kid"])
+		assert.Equal("testIssuer", payload["iss"])
+		assert.Equal(custom["test1k"], payload["test1k"])
+		assert.Equal(custom["test2k"], payload["test2k"])
+		assert.Equal(float64(60), payload["exp"])
+		assert.Equal(float64(issuedAt.Unix()), payload[OriginalIssuedAtClaim])
+
+		iat, err := payload.iat()
+
+		assert.Nil(err)
+		assert.True(iat.After(issuedAt))
+	})
+
+	t.Run("Should use ExpiresIn when given", func(t *testing.T) {
+		refreshed, err := Refresh(sign(time.Minute), "key", &RefreshOption{
+			ExpiresIn:   time.Hour,
+			GracePeriod: 2 * time.Hour,
+		})
+
+		assert.Nil(err)
+
+		_, payload, err := Verify(refreshed, "key", nil)
+
+		assert.Nil(err)
+		assert.Equal(float64(3600), payload["exp"])
+	})
+
+	t.Run("Should return ErrRefreshExpired when out of grace period", func(t *testing.T) {
+		_, err := Refresh(sign(time.Minute), "key", &RefreshOption{
+			GracePeriod: time.Minute,
+		})
+
+		assert.Equal(ErrRefreshExpired, err)
+	})
+
+	t.Run("Should return ErrInvalidSignature when key is wrong", func(t *testing.T) {
+		_, err := Refresh(sign(time.Minute), "key1", &RefreshOption{
+			GracePeriod: 2 * time.Hour,
+		})
+
+		assert.Equal(ErrInvalidSignature, err)
+	})
+
+	t.Run("Should return ErrMaxLifetimeExceeded when session is too old", func(t *testing.T) {
+		_, err := Refresh(sign(2*time.Hour), "key", &RefreshOption{
+			MaxLifetime: 30 * time.Minute,
+		})
+
+		assert.Equal(ErrMaxLifetimeExceeded, err)
+	})
+
+	t.Run("Should keep original issue time and cap lifetime across refreshes", func(t *testing.T) {
+		opt := &RefreshOption{
+			MaxLifetime: 90 * time.Minute,
+			Clock:       clockAt(issuedAt.Add(30 * time.Minute)),
+		}
+
+		refreshed, err := Refresh(sign(time.Hour), "key", opt)
+
+		assert.Nil(err)
+
+		opt.Clock = clockAt(issuedAt.Add(80 * time.Minute))
+
+		refreshed, err = Refresh(refreshed, "key", opt)
+
+		assert.Nil(err)
+
+		_, payload, err := Verify(refreshed, "key", &VerifyOption{
+			Clock: clockAt(issuedAt.Add(80 * time.Minute)),
+		})
+
+		assert.Nil(err)
+		assert.Equal(float64(issuedAt.Unix()), payload[OriginalIssuedAtClaim])
+		assert.Equal(float64(600), payload["exp"])
+
+		opt.Clock = clockAt(issuedAt.Add(95 * time.Minute))
+
+		_, err = Refresh(refreshed, "key", opt)
+
+		assert.Equal(ErrMaxLifetimeExceeded, err)
+	})
+
+	t.Run("Should return ErrTokenRevoked when token is revoked", func(t *testing.T) {
+		revoker := NewMemoryRevoker()
+		token := sign(2 * time.Hour)
+
+		assert.Nil(Revoke(token, "key", revoker, nil))
+
+		_, err := Refresh(token, "key", &RefreshOption{GracePeriod: 2 * time.Hour, Revoker: revoker})
+
+		assert.Equal(ErrTokenRevoked, err)
+	})
+
+	t.Run("Should return ErrTokenRevoked when revoked token expired within grace period", func(t *testing.T) {
+		revoker := NewMemoryRevoker()
+		token, err := Sign(custom, "key", &SignOption{
+			ExpiresIn: time.Minute,
+			Clock:     func() time.Time { return time.Now().Add(-2 * time.Minute) },
+		})
+
+		assert.Nil(err)
+		assert.Nil(Revoke(token, "key", revoker, &RevokeOption{GracePeriod: time.Hour}))
+
+		_, err = Refresh(token, "key", &RefreshOption{GracePeriod: time.Hour, Revoker: revoker})
+
+		assert.Equal(ErrTokenRevoked, err)
+	})
+
+	t.Run("Should preserve typ, encoding and renew jti", func(t *testing.T) {
+		token, err := Sign(Payload{"jti": "testJti"}, "key", &SignOption{
+			Type:           "at+jwt",
+			ExpiresIn:      time.Minute,
+			RawURLEncoding: true,
+		})
+
+		assert.Nil(err)
+
+		_, err = Refresh(token, "key", nil)
+
+		assert.Equal(ErrInvalidHeaderType, err)
+
+		refreshed, err := Refresh(token, "key", &RefreshOption{Types: []string{"at+jwt"}})
+
+		assert.Nil(err)
+		assert.False(bytes.ContainsAny(refreshed, "+/="))
+
+		header, payload, err := Verify(refreshed, "key", &VerifyOption{Types: []string{"at+jwt"}})
+
+		assert.Nil(err)
+		assert.Equal("at+jwt", header["typ"])
+		assert.NotEmpty(payload["jti"])
+		assert.NotEqual("testJti", payload["jti"])
+
+		token, err = Sign(Payload{}, "key", &SignOption{OmitType: true, ExpiresIn: time.Minute})
+
+		assert.Nil(err)
+
+		refreshed, err = Refresh(token, "key", &RefreshOption{AllowMissingType: true})
+
+		assert.Nil(err)
+		assert.True(bytes.ContainsAny(refreshed, "+/="))
+
+		header, payload, err = Verify(refreshed, "key", &VerifyOption{AllowMissingType: true})
+
+		assert.Nil(err)
+		assert.Nil(header["typ"])
+		assert.Nil(payload["jti"])
+	})
+
+	t.Run("Should return ErrTokenIssuedBeforeCutoff when issued before cutoff", func(t *testing.T) {
+		sc := NewMemorySubjectCutoff()
+		token, err := Sign(custom, "key", &SignOption{
+			Subject:   "testSubject",
+			ExpiresIn: time.Minute,
+			Clock:     clockAt(issuedAt),
+		})
+
+		assert.Nil(err)
+		assert.Nil(sc.RevokeBefore("testSubject", issuedAt.Add(time.Second)))
+
+		_, err = Refresh(token, "key", &RefreshOption{GracePeriod: 2 * time.Hour, SubjectCutoff: sc})
+
+		assert.Equal(ErrTokenIssuedBeforeCutoff, err)
+	})
+}
@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Revoker represents a denylist of revoked tokens which is consulted by
+// Verify. Tokens are identified by their "jti" claim, or by the SHA-256 hash
+// of their decoded signature when "jti" is absent.
+type Revoker interface {
+	// Revoke adds the token identified by id to the denylist until expiresAt,
+	// the entry never expires if expiresAt is zero.
+	Revoke(id string, expiresAt time.Time) error
+	// IsRevoked reports whether the token identified by id is revoked.
+	IsRevoked(id string) (bool, error)
+}
+
+// RevokeOption represents the options of Revoke.
+type RevokeOption struct {
+	// VerifyOption is the option to verify the token with, its expiration is
+	// ignored.
+	VerifyOption *VerifyOption
+	// GracePeriod specifies how long after its expiration the token is kept
+	// in the denylist, it should be the GracePeriod of Refresh so that the
+	// revoked token can not be refreshed.
+	GracePeriod time.Duration
+}
+
+// Revoke verifies the given token, ignoring its expiration, and adds it to
+// the denylist of revoker until the token expires and the grace period
+// passes.
+func Revoke(token []byte, secretOrPrivateKey interface{}, revoker Revoker, opt *RevokeOption) error {
+	var o VerifyOption
+
+	if opt == nil {
+		opt = &RevokeOption{}
+	}
+
+	if opt.VerifyOption != nil {
+		o = *opt.VerifyOption
+	}
+
+	o.IngoreExpiration = true
+	o.Revoker = nil
+
+	_, payload, err := Verify(token, secretOrPrivateKey, &o)
+
+	if err != nil {
+		return err
+	}
+
+	pt, err := parse(token, &o)
+
+	if err != nil {
+		return err
+	}
+
+	var exp time.Time
+
+	if o.AbsoluteExpiration {
+		exp, _ = payload.absoluteExpTime()
+	} else {
+		exp, _ = payload.expTime()
+	}
+
+	if !exp.IsZero() {
+		exp = exp.Add(opt.GracePeriod)
+	}
+
+	return revoker.Revoke(tokenID(pt.signature, payload), exp)
+}
+
+// tokenID returns the identifier of the token, which is computed from the
+// decoded signature rather than the token, since different encodings of the
+// same signature are accepted.
+func tokenID(signature []byte, payload Payload) string {
+	if jti, ok := payload["jti"].(string); ok && jti != "" {
+		return jti
+	}
+
+	sum := sha256.Sum256(signature)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func isRevoked(store *timeStore, id string) bool {
+	expiresAt, ok := store.get(id)
+
+	return ok && !isStaleRevocation(expiresAt)
+}
+
+func isStaleRevocation(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && !time.Now().Before(expiresAt)
+}
+
+// MemoryRevoker is a Revoker which keeps the denylist in memory.
+type MemoryRevoker struct {
+	store *timeStore
+}
+
+// NewMemoryRevoker returns a new empty MemoryRevoker.
+func NewMemoryRevoker() *MemoryRevoker {
+	store, _ := newTimeStore("")
+
+	return &MemoryRevoker{store: store}
+}
+
+// Revoke implements Revoker.
+func (mr *MemoryRevoker) Revoke(id string, expiresAt time.Time) error {
+	return mr.store.set(id, expiresAt, isStaleRevocation)
+}
+
+// IsRevoked implements Revoker.
+func (mr *MemoryRevoker) IsRevoked(id string) (bool, error) {
+	return isRevoked(mr.store, id), nil
+}
+
+// FileRevoker is a Revoker which persists the denylist to a JSON file, so
+// that revocations survive restarts.
+type FileRevoker struct {
+	store *timeStore
+}
+
+// NewFileRevoker returns a FileRevoker backed by the file at path, loading
+// the revocations already stored in it. The file will be created on the
+// first revocation if it does not exist.
+func NewFileRevoker(path string) (*FileRevoker, error) {
+	store, err := newTimeStore(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileRevoker{store: store}, nil
+}
+
+// Revoke implements Revoker.
+func (fr *FileRevoker) Revoke(id string, expiresAt time.Time) error {
+	return fr.store.set(id, expiresAt, isStaleRevocation)
+}
+
+// IsRevoked implements Revoker.
+func (fr *FileRevoker) IsRevoked(id string) (bool, error) {
+	return isRevoked(fr.store, id), nil
+}
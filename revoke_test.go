@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevoke(t *testing.T) {
+	assert := assert.New(t)
+
+	signOpt := &SignOption{ExpiresIn: time.Minute}
+
+	t.Run("Should return ErrTokenRevoked when token is revoked by jti", func(t *testing.T) {
+		revoker := NewMemoryRevoker()
+
+		token, err := Sign(Payload{"jti": "testJti"}, "key", signOpt)
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{Revoker: revoker})
+
+		assert.Nil(err)
+
+		assert.Nil(Revoke(token, "key", revoker, nil))
+
+		revoked, err := revoker.IsRevoked("testJti")
+
+		assert.Nil(err)
+		assert.True(revoked)
+
+		_, _, err = Verify(token, "key", &VerifyOption{Revoker: revoker})
+
+		assert.Equal(ErrTokenRevoked, err)
+	})
+
+	t.Run("Should return ErrTokenRevoked when token without jti is revoked", func(t *testing.T) {
+		revoker := NewMemoryRevoker()
+
+		token, err := Sign(Payload{"foo": "bar"}, "key", signOpt)
+
+		assert.Nil(err)
+
+		other, err := Sign(Payload{"foo": "baz"}, "key", signOpt)
+
+		assert.Nil(err)
+
+		assert.Nil(Revoke(token, "key", revoker, nil))
+
+		_, _, err = Verify(token, "key", &VerifyOption{Revoker: revoker})
+
+		assert.Equal(ErrTokenRevoked, err)
+
+		_, _, err = Verify(other, "key", &VerifyOption{Revoker: revoker})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should not accept re-encoded signature of revoked token", func(t *testing.T) {
+		revoker := NewMemoryRevoker()
+
+		token, err := Sign(Payload{"foo": "bar"}, "key", signOpt)
+
+		assert.Nil(err)
+		assert.Nil(Revoke(token, "key", revoker, nil))
+
+		const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+		// The last character of the 32-byte signature carries 2 unused bits.
+		i := len(token) - 2
+		tampered := append([]byte{}, token...)
+		tampered[i] = alphabet[strings.IndexByte(alphabet, token[i])^1]
+
+		withNewline := append(append(append([]byte{}, token[:i]...), '\n'), token[i:]...)
+
+		for _, token := range [][]byte{tampered, withNewline} {
+			_, _, err = Verify(token, "key", &VerifyOption{Revoker: revoker})
+
+			assert.NotNil(err, string(token))
+		}
+	})
+
+	t.Run("Should not revoke token with invalid signature", func(t *testing.T) {
+		token, err := Sign(Payload{"jti": "testJti"}, "key", signOpt)
+
+		assert.Nil(err)
+
+		assert.Equal(ErrInvalidSignature, Revoke(token, "key1", NewMemoryRevoker(), nil))
+	})
+
+	t.Run("Should expire entries at the given time", func(t *testing.T) {
+		revoker := NewMemoryRevoker()
+
+		assert.Nil(revoker.Revoke("expired", time.Now().Add(-time.Second)))
+		assert.Nil(revoker.Revoke("forever", time.Time{}))
+
+		revoked, err := revoker.IsRevoked("expired")
+
+		assert.Nil(err)
+		assert.False(revoked)
+
+		revoked, err = revoker.IsRevoked("forever")
+
+		assert.Nil(err)
+		assert.True(revoked)
+	})
+
+	t.Run("Should persist revocations to file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "jwt")
+
+		assert.Nil(err)
+
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "revoked.json")
+
+		revoker, err := NewFileRevoker(path)
+
+		assert.Nil(err)
+		assert.Nil(revoker.Revoke("testJti", time.Now().Add(time.Minute)))
+		assert.Nil(revoker.Revoke("expired", time.Now().Add(-time.Minute)))
+
+		revoker, err = NewFileRevoker(path)
+
+		assert.Nil(err)
+
+		revoked, err := revoker.IsRevoked("testJti")
+
+		assert.Nil(err)
+		assert.True(revoked)
+
+		revoked, err = revoker.IsRevoked("expired")
+
+		assert.Nil(err)
+		assert.False(revoked)
+	})
+
+	t.Run("Should return error when file is not valid json", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "jwt")
+
+		assert.Nil(err)
+
+		defer os.Remove(f.Name())
+
+		f.WriteString("{")
+		f.Close()
+
+		_, err = NewFileRevoker(f.Name())
+
+		assert.NotNil(err)
+	})
+}
@@ -1,11 +1,9 @@
 package jwt
 
 import (
-	"bytes"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
-	"encoding/base64"
 )
 
 func init() {
@@ -32,26 +30,25 @@ func (ra rsaAlgImp) sign(content []byte, privateKey interface{}) ([]byte, error)
 	return rsa.SignPKCS1v15(rand.Reader, key, ra.hash, h.Sum(nil))
 }
 
-func (ra rsaAlgImp) verify(token []byte, privateKey interface{}) (header Header, payload Payload, err error) {
-	if header, payload, err = decode(token); err != nil {
-		return
-	}
-
-	signatureReceive, err := base64.StdEncoding.DecodeString(string(bytes.Split(token, periodBytes)[2]))
+func (ra rsaAlgImp) verify(content, signature []byte, key interface{}) error {
+	var publicKey *rsa.PublicKey
 
-	if err != nil {
-		return
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		publicKey = &k.PublicKey
+	case *rsa.PublicKey:
+		publicKey = k
+	default:
+		return ErrInvalidKeyType
 	}
 
-	signatureExpect, err := ra.sign(token[0:bytes.LastIndexByte(token, '.')], privateKey)
+	h := ra.hash.New()
 
-	if err != nil {
-		return
-	}
+	h.Write(content)
 
-	if !bytes.Equal(signatureReceive, signatureExpect) {
-		return nil, nil, ErrInvalidSignature
+	if err := rsa.VerifyPKCS1v15(publicKey, ra.hash, h.Sum(nil), signature); err != nil {
+		return ErrInvalidSignature
 	}
 
-	return
+	return nil
 }
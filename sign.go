@@ -2,8 +2,8 @@ package jwt
 
 import (
 	"bytes"
+	"crypto/x509"
 	"encoding/base64"
-	"encoding/json"
 	"time"
 
 	"github.com/imdario/mergo"
@@ -16,8 +16,30 @@ type SignOption struct {
 	Audience  string
 	Issuer    string
 	Subject   string
+	// Type specifies the "typ" header, such as "at+jwt" for explicitly typed
+	// tokens, "JWT" will be used if it is empty.
+	Type string
+	// OmitType specifies whether to omit the "typ" header.
+	OmitType bool
 	// Header is the customized header which will be merged to token's header.
 	Header Header
+	// CertificateChain is the certificate chain of the signing key, which will
+	// be embedded in the "x5c" header along with the "x5t" and "x5t#S256"
+	// thumbprints of its first certificate if it is not empty.
+	CertificateChain []*x509.Certificate
+	// UnencodedPayload specifies whether SignDetached signs the content as is
+	// instead of base64 encoding it, using the "b64" header of RFC 7797.
+	UnencodedPayload bool
+	// RawURLEncoding specifies whether Sign encodes the segments with unpadded
+	// base64url as RFC 7515 requires, instead of padded standard base64 which
+	// older versions of Verify expect.
+	RawURLEncoding bool
+	// Codec specifies the Codec to marshal the header and payload with, the
+	// default Codec will be used if it is nil.
+	Codec Codec
+	// Clock returns the time used as "iat" of the token, time.Now will be
+	// used if it is nil.
+	Clock func() time.Time
 }
 
 // Sign signs the given payload and serect to the JSON web token,
@@ -34,48 +56,84 @@ func Sign(payload Payload, secretOrPrivateKey interface{}, opt *SignOption) (tok
 		opt = &SignOption{}
 	}
 
+	if opt.Algorithm == "" {
+		opt.Algorithm = HS256
+	}
+
 	if secretOrPrivateKey == nil {
 		return nil, ErrEmptySecretOrPrivateKey
 	}
 
-	var headerJSON, payloadJSON, signature []byte
+	if len(opt.CertificateChain) > 0 && !certificateMatchesKey(opt.CertificateChain[0], secretOrPrivateKey) {
+		return nil, ErrInvalidKeyType
+	}
+
+	var headerJSON, payloadJSON, sigBase64 []byte
 
 	if headerJSON, err = marshalHeader(opt); err != nil {
 		return
 	}
 
-	hBase64 := []byte(base64.StdEncoding.EncodeToString(headerJSON))
+	enc := base64.StdEncoding
+
+	if opt.RawURLEncoding {
+		enc = base64.RawURLEncoding
+	}
+
+	hBase64 := []byte(enc.EncodeToString(headerJSON))
 
 	if payloadJSON, err = marshalPayload(payload, opt); err != nil {
 		return
 	}
 
-	pBase64 := []byte(base64.StdEncoding.EncodeToString(payloadJSON))
+	pBase64 := []byte(enc.EncodeToString(payloadJSON))
 
-	if opt.Algorithm == "" {
-		opt.Algorithm = HS256
+	if sigBase64, err = signContent(bytes.Join([][]byte{hBase64, pBase64},
+		periodBytes), secretOrPrivateKey, opt.Algorithm, enc); err != nil {
+		return
 	}
 
-	algImp, ok := algImpMap[opt.Algorithm]
+	return bytes.Join([][]byte{hBase64, pBase64, sigBase64}, periodBytes), nil
+}
+
+// signContent returns the signature of content encoded with enc.
+func signContent(content []byte, secretOrPrivateKey interface{}, alg Algorithm, enc *base64.Encoding) ([]byte, error) {
+	algImp, ok := algImpMap[alg]
 
 	if !ok {
 		return nil, ErrInvalidAlgorithm
 	}
 
-	if signature, err = algImp.sign(bytes.Join([][]byte{hBase64, pBase64},
-		periodBytes), secretOrPrivateKey); err != nil {
-		return
-	}
+	signature, err := algImp.sign(content, secretOrPrivateKey)
 
-	sigBase64 := []byte(base64.StdEncoding.EncodeToString(signature))
+	if err != nil {
+		return nil, err
+	}
 
-	return bytes.Join([][]byte{hBase64, pBase64, sigBase64}, periodBytes), nil
+	return []byte(enc.EncodeToString(signature)), nil
 }
 
 func marshalHeader(opt *SignOption) ([]byte, error) {
-	h := map[string]interface{}{
-		"alg": opt.Algorithm,
-		"typ": "JWT",
+	h := map[string]interface{}{"alg": opt.Algorithm}
+
+	if !opt.OmitType {
+		h["typ"] = "JWT"
+
+		if opt.Type != "" {
+			h["typ"] = opt.Type
+		}
+	}
+
+	return mergeHeader(h, opt)
+}
+
+// mergeHeader merges the certificate chain and custom header of opt into h
+// and marshals it.
+func mergeHeader(h map[string]interface{}, opt *SignOption) ([]byte, error) {
+	if len(opt.CertificateChain) > 0 {
+		if err := mergo.Map(&h, marshalX5C(opt.CertificateChain)); err != nil {
+			return nil, err
+		}
 	}
 
 	if opt.Header != nil {
@@ -84,11 +142,11 @@ func marshalHeader(opt *SignOption) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(h)
+	return jsonCodec(opt.Codec).Marshal(h)
 }
 
 func marshalPayload(payload Payload, opt *SignOption) ([]byte, error) {
-	claims := Payload{"iat": time.Now().Unix()}
+	claims := Payload{"iat": currentTime(opt.Clock).Unix()}
 
 	if opt.Issuer != "" {
 		claims["iss"] = opt.Issuer
@@ -107,5 +165,5 @@ func marshalPayload(payload Payload, opt *SignOption) ([]byte, error) {
 		return nil, err
 	}
 
-	return json.Marshal(claims)
+	return jsonCodec(opt.Codec).Marshal(claims)
 }
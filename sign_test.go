@@ -159,6 +159,11 @@ func TestSign(t *testing.T) {
 
 		assert.Nil(err)
 		assert.Equal(3, len(bytes.Split(signed, periodBytes)))
+
+		header, _, err := Decode(signed)
+
+		assert.Nil(err)
+		assert.Equal(string(HS256), header["alg"])
 	})
 
 	t.Run("Should return with three parts and using RSA", func(t *testing.T) {
@@ -184,4 +189,19 @@ func TestSign(t *testing.T) {
 		assert.Nil(err)
 		assert.Equal(3, len(bytes.Split(signed, periodBytes)))
 	})
+
+	t.Run("Should encode segments with base64url when RawURLEncoding", func(t *testing.T) {
+		signed, err := Sign(map[string]interface{}{"foo": "~~~>>>???"}, "key", &SignOption{
+			ExpiresIn:      time.Minute,
+			RawURLEncoding: true,
+		})
+
+		assert.Nil(err)
+		assert.False(bytes.ContainsAny(signed, "+/="))
+
+		_, payload, err := Verify(signed, "key", nil)
+
+		assert.Nil(err)
+		assert.Equal("~~~>>>???", payload["foo"])
+	})
 }
@@ -0,0 +1,114 @@
+package jwt
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// timeStore is a concurrency safe map from string keys to times, which will
+// be persisted to a JSON file if path is not empty.
+type timeStore struct {
+	mu   sync.RWMutex
+	path string
+	m    map[string]time.Time
+}
+
+func newTimeStore(path string) (*timeStore, error) {
+	s := &timeStore{path: path, m: map[string]time.Time{}}
+
+	if path == "" {
+		return s, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) == 0 {
+		return s, nil
+	}
+
+	if err = json.Unmarshal(b, &s.m); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *timeStore) get(key string) (t time.Time, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok = s.m[key]
+
+	return
+}
+
+// set stores t under key, removes the entries for which stale returns true,
+// and persists the result.
+func (s *timeStore) set(key string, t time.Time, stale func(time.Time) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.m[key] = t
+
+	if stale != nil {
+		for k, v := range s.m {
+			if stale(v) {
+				delete(s.m, k)
+			}
+		}
+	}
+
+	return s.persist()
+}
+
+// add is like set, but only stores t if key is absent or its entry is stale,
+// and reports whether t was stored.
+func (s *timeStore) add(key string, t time.Time, stale func(time.Time) bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v, ok := s.m[key]; ok && !stale(v) {
+		return false, nil
+	}
+
+	s.m[key] = t
+
+	for k, v := range s.m {
+		if stale(v) {
+			delete(s.m, k)
+		}
+	}
+
+	return true, s.persist()
+}
+
+func (s *timeStore) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(s.m)
+
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+
+	if err = ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,136 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxNestingDepth is the nesting depth of JSON objects and arrays
+// allowed by StrictOption when MaxNestingDepth is zero.
+const DefaultMaxNestingDepth = 32
+
+// StrictOption represents the options of strict decoding, which rejects
+// header and payload with duplicate member names or nested too deeply, and
+// decodes integers fitting in int64 as int64 and other numbers as
+// json.Number instead of float64.
+type StrictOption struct {
+	// MaxNestingDepth specifies the maximum nesting depth of objects and
+	// arrays, counting the header or payload itself. DefaultMaxNestingDepth
+	// will be used if it is zero.
+	MaxNestingDepth int
+}
+
+type strictDecoder struct {
+	dec      *json.Decoder
+	maxDepth int
+}
+
+// decodeStrict decodes the JSON object data according to opt.
+func decodeStrict(data []byte, opt *StrictOption) (map[string]interface{}, error) {
+	d := &strictDecoder{dec: json.NewDecoder(bytes.NewReader(data)), maxDepth: opt.MaxNestingDepth}
+
+	d.dec.UseNumber()
+
+	if d.maxDepth == 0 {
+		d.maxDepth = DefaultMaxNestingDepth
+	}
+
+	v, err := d.value(0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := v.(map[string]interface{})
+
+	if !ok {
+		return nil, fmt.Errorf("%w: not an object", ErrInvalidJSON)
+	}
+
+	if _, err = d.dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidJSON)
+	}
+
+	return m, nil
+}
+
+// value decodes the next value, whose containers are nested depth deep.
+func (d *strictDecoder) value(depth int) (interface{}, error) {
+	t, err := d.dec.Token()
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := t.(type) {
+	case json.Delim:
+		if depth >= d.maxDepth {
+			return nil, fmt.Errorf("%w: nested deeper than %d", ErrInvalidJSON, d.maxDepth)
+		}
+
+		if v == '{' {
+			return d.object(depth + 1)
+		}
+
+		return d.array(depth + 1)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+
+		return v, nil
+	}
+
+	return t, nil
+}
+
+func (d *strictDecoder) object(depth int) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+
+	for d.dec.More() {
+		t, err := d.dec.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		key := t.(string)
+
+		if _, ok := m[key]; ok {
+			return nil, fmt.Errorf("%w: duplicate key %q", ErrInvalidJSON, key)
+		}
+
+		if m[key], err = d.value(depth); err != nil {
+			return nil, err
+		}
+	}
+
+	// Consumes the closing delimiter.
+	if _, err := d.dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (d *strictDecoder) array(depth int) ([]interface{}, error) {
+	a := []interface{}{}
+
+	for d.dec.More() {
+		v, err := d.value(depth)
+
+		if err != nil {
+			return nil, err
+		}
+
+		a = append(a, v)
+	}
+
+	if _, err := d.dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
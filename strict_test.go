@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictDecoding(t *testing.T) {
+	assert := assert.New(t)
+
+	signRaw := func(header, payload string) []byte {
+		content := base64.StdEncoding.EncodeToString([]byte(header)) + "." +
+			base64.StdEncoding.EncodeToString([]byte(payload))
+
+		sig, err := signContent([]byte(content), "key", HS256, base64.StdEncoding)
+
+		assert.Nil(err)
+
+		return []byte(content + "." + string(sig))
+	}
+
+	header := `{"alg":"HS256","typ":"JWT"}`
+	iat := time.Now().Unix()
+
+	t.Run("Should preserve integer precision", func(t *testing.T) {
+		token := signRaw(header, `{"iat":`+strconv.FormatInt(iat, 10)+`,"exp":60,"id":9007199254740993,"ratio":0.5}`)
+
+		_, payload, err := Verify(token, "key", &VerifyOption{Strict: &StrictOption{}})
+
+		assert.Nil(err)
+		assert.Equal(int64(9007199254740993), payload["id"])
+		assert.Equal(json.Number("0.5"), payload["ratio"])
+		assert.Equal(iat, payload["iat"])
+
+		_, payload, err = Verify(token, "key", nil)
+
+		assert.Nil(err)
+		assert.Equal(float64(9007199254740992), payload["id"])
+	})
+
+	t.Run("Should return ErrInvalidJSON when keys are duplicated", func(t *testing.T) {
+		token := signRaw(header, `{"iat":`+strconv.FormatInt(iat, 10)+`,"exp":60,"sub":"alice","sub":"admin"}`)
+
+		_, _, err := Verify(token, "key", &VerifyOption{Strict: &StrictOption{}})
+
+		assert.True(errors.Is(err, ErrInvalidJSON))
+
+		token = signRaw(`{"alg":"HS256","typ":"JWT","typ":"JWT"}`, `{"iat":`+strconv.FormatInt(iat, 10)+`,"exp":60}`)
+
+		_, _, err = Verify(token, "key", &VerifyOption{Strict: &StrictOption{}})
+
+		assert.True(errors.Is(err, ErrInvalidJSON))
+	})
+
+	t.Run("Should return ErrInvalidJSON when nested too deeply", func(t *testing.T) {
+		nested := strings.Repeat("[", 5) + strings.Repeat("]", 5)
+		token := signRaw(header, `{"iat":`+strconv.FormatInt(iat, 10)+`,"exp":60,"n":`+nested+`}`)
+
+		_, _, err := Verify(token, "key", &VerifyOption{Strict: &StrictOption{MaxNestingDepth: 6}})
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{Strict: &StrictOption{MaxNestingDepth: 5}})
+
+		assert.True(errors.Is(err, ErrInvalidJSON))
+	})
+
+	t.Run("Should return error when JSON is malformed", func(t *testing.T) {
+		for _, s := range []string{`{"a":1`, `{"a":1}{}`, `[]`, `{"a":}`} {
+			_, err := decodeStrict([]byte(s), &StrictOption{})
+
+			assert.NotNil(err, s)
+		}
+	})
+}
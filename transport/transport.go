@@ -0,0 +1,143 @@
+// Package transport provides an http.RoundTripper which authenticates
+// outgoing requests with JSON web tokens.
+package transport
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/DavidCai1993/jwt"
+)
+
+// DefaultRefreshBefore is how long before its expiration a cached token is
+// re-signed when Transport.RefreshBefore is zero.
+const DefaultRefreshBefore = 10 * time.Second
+
+// Transport is an http.RoundTripper which signs a token for the audience of
+// each request, caches it until shortly before it expires, and sets it in the
+// Authorization header. It is safe for concurrent use, concurrent requests to
+// the same audience wait for a single signing.
+type Transport struct {
+	// Base is the underlying RoundTripper, http.DefaultTransport will be used
+	// if it is nil.
+	Base http.RoundTripper
+	// Key is the secret or private key passed to jwt.Sign.
+	Key interface{}
+	// Payload is the custom claims of the tokens.
+	Payload jwt.Payload
+	// SignOption is the option passed to jwt.Sign, its Audience is replaced by
+	// the audience of each request.
+	SignOption *jwt.SignOption
+	// Audience returns the audience of the request, the host of the request
+	// URL will be used if it is nil.
+	Audience func(r *http.Request) string
+	// RefreshBefore specifies how long before its expiration a cached token is
+	// re-signed.
+	RefreshBefore time.Duration
+
+	mu     sync.Mutex
+	tokens map[string]*cachedToken
+}
+
+type cachedToken struct {
+	mu        sync.Mutex
+	token     []byte
+	refreshAt time.Time
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	audience := r.URL.Host
+
+	if t.Audience != nil {
+		audience = t.Audience(r)
+	}
+
+	token, err := t.token(audience)
+
+	if err != nil {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+
+		return nil, err
+	}
+
+	r2 := r.Clone(r.Context())
+	r2.Header.Set("Authorization", "Bearer "+string(token))
+
+	base := t.Base
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(r2)
+}
+
+func (t *Transport) token(audience string) ([]byte, error) {
+	t.mu.Lock()
+
+	if t.tokens == nil {
+		t.tokens = map[string]*cachedToken{}
+	}
+
+	ct, ok := t.tokens[audience]
+
+	if !ok {
+		ct = &cachedToken{}
+		t.tokens[audience] = ct
+	}
+
+	t.mu.Unlock()
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	var opt jwt.SignOption
+
+	if t.SignOption != nil {
+		opt = *t.SignOption
+	}
+
+	now := time.Now()
+
+	if opt.Clock != nil {
+		now = opt.Clock()
+	}
+
+	if ct.token != nil && (ct.refreshAt.IsZero() || now.Before(ct.refreshAt)) {
+		return ct.token, nil
+	}
+
+	opt.Audience = audience
+	opt.Clock = func() time.Time { return now }
+
+	payload := jwt.Payload{}
+
+	for k, v := range t.Payload {
+		payload[k] = v
+	}
+
+	token, err := jwt.Sign(payload, t.Key, &opt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ct.token = token
+	ct.refreshAt = time.Time{}
+
+	if opt.ExpiresIn != 0 {
+		refreshBefore := t.RefreshBefore
+
+		if refreshBefore == 0 {
+			refreshBefore = DefaultRefreshBefore
+		}
+
+		ct.refreshAt = now.Add(opt.ExpiresIn - refreshBefore)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DavidCai1993/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		_, payload, err := jwt.Verify([]byte(token), "key", &jwt.VerifyOption{
+			Audience: r.Host,
+			Issuer:   "testIssuer",
+		})
+
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		received = append(received, token)
+		mu.Unlock()
+
+		w.Write([]byte(payload["foo"].(string)))
+	}))
+
+	defer server.Close()
+
+	t.Run("Should sign token for the host of request", func(t *testing.T) {
+		received = nil
+
+		client := &http.Client{Transport: &Transport{
+			Key:        "key",
+			Payload:    jwt.Payload{"foo": "bar"},
+			SignOption: &jwt.SignOption{Issuer: "testIssuer", ExpiresIn: time.Minute},
+		}}
+
+		r, err := http.NewRequest("GET", server.URL, nil)
+
+		assert.Nil(err)
+
+		res, err := client.Do(r)
+
+		assert.Nil(err)
+		assert.Equal(http.StatusOK, res.StatusCode)
+		assert.Equal("", r.Header.Get("Authorization"))
+		res.Body.Close()
+	})
+
+	t.Run("Should cache token under concurrency", func(t *testing.T) {
+		received = nil
+
+		client := &http.Client{Transport: &Transport{
+			Key:        "key",
+			Payload:    jwt.Payload{"foo": "bar"},
+			SignOption: &jwt.SignOption{Issuer: "testIssuer", ExpiresIn: time.Minute},
+		}}
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				res, err := client.Get(server.URL)
+
+				assert.Nil(err)
+				assert.Equal(http.StatusOK, res.StatusCode)
+				res.Body.Close()
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(10, len(received))
+
+		for _, token := range received {
+			assert.Equal(received[0], token)
+		}
+	})
+
+	t.Run("Should re-sign token shortly before expiration", func(t *testing.T) {
+		received = nil
+		start := time.Now().Add(-50 * time.Second)
+		now := start
+
+		transport := &Transport{
+			Key:     "key",
+			Payload: jwt.Payload{"foo": "bar"},
+			SignOption: &jwt.SignOption{
+				Issuer:    "testIssuer",
+				ExpiresIn: time.Minute,
+				Clock:     func() time.Time { return now },
+			},
+			RefreshBefore: 20 * time.Second,
+		}
+
+		client := &http.Client{Transport: transport}
+
+		for _, elapsed := range []time.Duration{0, 30 * time.Second, 45 * time.Second} {
+			now = start.Add(elapsed)
+
+			res, err := client.Get(server.URL)
+
+			assert.Nil(err)
+			res.Body.Close()
+		}
+
+		assert.Equal(3, len(received))
+		assert.Equal(received[0], received[1])
+		assert.NotEqual(received[1], received[2])
+	})
+
+	t.Run("Should use the given Audience", func(t *testing.T) {
+		client := &http.Client{Transport: &Transport{
+			Key:        "key",
+			Payload:    jwt.Payload{"foo": "bar"},
+			SignOption: &jwt.SignOption{Issuer: "testIssuer"},
+			Audience:   func(*http.Request) string { return "other" },
+		}}
+
+		res, err := client.Get(server.URL)
+
+		assert.Nil(err)
+		assert.Equal(http.StatusUnauthorized, res.StatusCode)
+		res.Body.Close()
+	})
+
+	t.Run("Should return error when sign failed", func(t *testing.T) {
+		client := &http.Client{Transport: &Transport{Payload: jwt.Payload{}}}
+
+		_, err := client.Get(server.URL)
+
+		assert.NotNil(err)
+	})
+}
@@ -0,0 +1,23 @@
+package jwt
+
+import "context"
+
+// Validator validates a token whose signature, time and claim checks have
+// passed, returning an error rejects the token with that error.
+type Validator func(ctx context.Context, header Header, payload Payload) error
+
+// runValidators runs the validators in order, stopping at the first error or
+// when ctx is done.
+func runValidators(ctx context.Context, validators []Validator, header Header, payload Payload) error {
+	for _, validate := range validators {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := validate(ctx, header, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+func TestValidators(t *testing.T) {
+	assert := assert.New(t)
+
+	token, err := Sign(Payload{"tenant": "acme"}, "key", &SignOption{ExpiresIn: time.Minute})
+
+	assert.Nil(err)
+
+	errInactive := errors.New("tenant inactive")
+
+	tenantActive := func(ctx context.Context, header Header, payload Payload) error {
+		if payload["tenant"] != ctx.Value(tenantKey{}) {
+			return errInactive
+		}
+
+		return nil
+	}
+
+	t.Run("Should pass context, header and payload to validators", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+		_, payload, err := VerifyContext(ctx, token, "key", &VerifyOption{Validators: []Validator{tenantActive}})
+
+		assert.Nil(err)
+		assert.Equal("acme", payload["tenant"])
+
+		_, _, err = Verify(token, "key", &VerifyOption{Validators: []Validator{tenantActive}})
+
+		assert.Equal(errInactive, err)
+	})
+
+	t.Run("Should run validators in order and stop at the first error", func(t *testing.T) {
+		calls := []int{}
+
+		validator := func(i int, err error) Validator {
+			return func(ctx context.Context, header Header, payload Payload) error {
+				calls = append(calls, i)
+
+				return err
+			}
+		}
+
+		_, _, err := Verify(token, "key", &VerifyOption{Validators: []Validator{
+			validator(1, nil), validator(2, errInactive), validator(3, nil),
+		}})
+
+		assert.Equal(errInactive, err)
+		assert.Equal([]int{1, 2}, calls)
+	})
+
+	t.Run("Should not run validators when other checks fail", func(t *testing.T) {
+		called := false
+
+		_, _, err := Verify(token, "key", &VerifyOption{
+			Issuer: "issuer",
+			Validators: []Validator{func(ctx context.Context, header Header, payload Payload) error {
+				called = true
+
+				return nil
+			}},
+		})
+
+		assert.Equal(ErrInvalidReservedClaim, err)
+		assert.False(called)
+	})
+
+	t.Run("Should return context error when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		_, _, err := VerifyContext(ctx, token, "key", &VerifyOption{Validators: []Validator{tenantActive}})
+
+		assert.Equal(context.Canceled, err)
+	})
+}
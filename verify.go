@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
 	"time"
 )
 
@@ -10,24 +13,135 @@ type VerifyOption struct {
 	Issuer    string
 	Audience  string
 	Subject   string
+	// Types specifies the accepted values of the "typ" header, compared case
+	// insensitively with the "application/" prefix optional. Only "JWT" will
+	// be accepted if it is empty.
+	Types []string
+	// AllowMissingType specifies whether to accept tokens without "typ".
+	AllowMissingType bool
+	// Claims specifies the rules which the claims of the token must meet.
+	Claims []ClaimRule
+	// RequireExpiration specifies whether the "exp" claim must be present,
+	// even if IngoreExpiration is true.
+	RequireExpiration bool
 	// IngoreExpiration specifies whether to validate the
 	// expiration of the token.
 	IngoreExpiration bool
+	// AbsoluteExpiration specifies whether "exp" is the NumericDate of RFC
+	// 7519, as in the tokens of other issuers, instead of the lifetime after
+	// "iat" which Sign produces.
+	AbsoluteExpiration bool
 	// ClockTolerance specifies the time duration to tolerate when
-	// checking the expiration of the token.
+	// checking the expiration, age and "iat" of the token.
 	ClockTolerance time.Duration
+	// MaxAge specifies the maximum time elapsed since the "iat" of the token
+	// regardless of its expiration, it is not limited if it is zero.
+	MaxAge time.Duration
+	// Clock returns the current time used when checking the expiration of the
+	// token, time.Now will be used if it is nil.
+	Clock func() time.Time
+	// Revoker specifies the denylist of revoked tokens to consult, revocation
+	// will not be checked if it is nil.
+	Revoker Revoker
+	// SubjectCutoff specifies the per-subject cutoffs to consult, tokens
+	// issued before the cutoff of their subject will be rejected.
+	SubjectCutoff SubjectCutoff
+	// X5C specifies how to validate the certificate chain in the "x5c" header.
+	// If it is not nil, the header is required and the public key of its leaf
+	// certificate is used to verify the signature instead of the given key.
+	X5C *X5COption
+	// TrustedJWKThumbprints specifies the RFC 7638 thumbprints of the keys
+	// which are trusted when embedded in the "jwk" header. If it is not empty
+	// and the header is present, the embedded key is used to verify the
+	// signature instead of the given key.
+	TrustedJWKThumbprints []string
+	// JKU specifies how to fetch the key from the JWK set referenced by the
+	// "jku" header. If it is not nil and the header is present, the fetched
+	// key is used to verify the signature instead of the given key.
+	JKU *JKUOption
+	// Codec specifies the Codec to unmarshal the header and payload with, the
+	// default Codec will be used if it is nil.
+	Codec Codec
+	// Strict specifies the options of strict decoding of the header and
+	// payload, which is used instead of Codec if it is not nil.
+	Strict *StrictOption
+	// Limits specifies the resource limits of the token, which are checked
+	// before decoding it if it is not nil.
+	Limits *LimitOption
+	// CriticalHeaders specifies the handlers of the custom header parameters
+	// which are understood when listed in the "crit" header, tokens listing
+	// other parameters are rejected.
+	CriticalHeaders map[string]CriticalHeaderHandler
+	// Validators specifies the checks run in order after all the other checks
+	// have passed, the first error returned rejects the token.
+	Validators []Validator
 }
 
 // Verify will return the decoded header and payload if the signature,
-// optional expiration, audience, issuer and subject are valid.
+// optional expiration, audience, issuer, subject and claim rules are valid
+// and the token is neither revoked nor issued before the cutoff of its
+// subject.
 // When using HMAC algorithm, secretOrPrivateKey's type should be string or []
 // byte , when using RSA algorithm, secretOrPrivateKey's type should be
-// rsa.PrivateKey. If the opt given is nil, it will use the defualt HS256
-// algorithm.
+// *rsa.PublicKey or *rsa.PrivateKey. If the opt given is nil, it will use the
+// defualt HS256 algorithm.
 func Verify(token []byte, secretOrPrivateKey interface{}, opt *VerifyOption) (header Header, payload Payload, err error) {
+	return VerifyContext(context.Background(), token, secretOrPrivateKey, opt)
+}
+
+// VerifyContext is like Verify, but passes ctx to the Validators of opt.
+func VerifyContext(ctx context.Context, token []byte, secretOrPrivateKey interface{}, opt *VerifyOption) (header Header, payload Payload, err error) {
+	header, payload, _, err = verify(ctx, token, secretOrPrivateKey, opt)
+
+	return
+}
+
+// VerifyX5C is like Verify, but verifies the signature with the public key of
+// the certificate chain in the "x5c" header, which is validated according to
+// opt.X5C, and also returns the verified chains.
+func VerifyX5C(token []byte, opt *VerifyOption) (header Header, payload Payload, chains [][]*x509.Certificate, err error) {
+	var o VerifyOption
+
+	if opt != nil {
+		o = *opt
+	}
+
+	if o.X5C == nil {
+		o.X5C = &X5COption{}
+	}
+
+	return verify(context.Background(), token, nil, &o)
+}
+
+// resolveKey returns the key to verify the signature with, which comes from
+// the first opted-in header of "x5c", "jwk" and "jku", or is the given key.
+func resolveKey(header Header, key interface{}, opt *VerifyOption, now time.Time) (interface{}, [][]*x509.Certificate, error) {
+	if opt.X5C != nil {
+		chains, leafKey, err := header.verifyX5C(opt.X5C, now)
+
+		return leafKey, chains, err
+	}
+
+	if _, ok := header["jwk"]; ok && len(opt.TrustedJWKThumbprints) > 0 {
+		key, err := header.embeddedJWKKey(opt.TrustedJWKThumbprints)
+
+		return key, nil, err
+	}
+
+	if _, ok := header["jku"]; ok && opt.JKU != nil {
+		key, err := opt.JKU.key(header)
+
+		return key, nil, err
+	}
+
+	return key, nil, nil
+}
+
+func verify(ctx context.Context, token []byte, key interface{}, opt *VerifyOption) (header Header, payload Payload, chains [][]*x509.Certificate, err error) {
 	var (
 		ok bool
 		ai algorithmImplementation
+		pt *parsedToken
 	)
 
 	if opt == nil {
@@ -39,27 +153,93 @@ func Verify(token []byte, secretOrPrivateKey interface{}, opt *VerifyOption) (he
 	}
 
 	if ai, ok = algImpMap[opt.Algorithm]; !ok {
-		return nil, nil, ErrInvalidAlgorithm
+		return nil, nil, nil, ErrInvalidAlgorithm
+	}
+
+	if opt.Limits != nil {
+		if err = opt.Limits.checkSize(token); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if pt, err = parse(token, opt); err != nil {
+		if errors.Is(err, ErrInvalidJSON) {
+			return nil, nil, nil, err
+		}
+
+		return nil, nil, nil, ErrInvalidSignature
+	}
+
+	if opt.Limits != nil {
+		if err = opt.Limits.checkClaims(pt.payload); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	now := currentTime(opt.Clock)
+
+	if key, chains, err = resolveKey(pt.header, key, opt, now); err != nil {
+		return nil, nil, nil, err
 	}
 
-	if header, payload, err = ai.verify(token, secretOrPrivateKey); err != nil {
-		return nil, nil, ErrInvalidSignature
+	if err = ai.verify(pt.content, pt.signature, key); err != nil {
+		return nil, nil, nil, ErrInvalidSignature
 	}
 
-	if !header.hasValidType() {
-		return nil, nil, ErrInvalidHeaderType
+	header, payload = pt.header, pt.payload
+
+	if err = header.checkCritical(payload, false, opt.CriticalHeaders); err != nil {
+		return nil, nil, nil, err
 	}
 
-	if !payload.checkStringClaim("aud", opt.Audience) ||
+	if _, ok = header["typ"]; (ok || !opt.AllowMissingType) && !header.hasValidType(opt.Types...) {
+		return nil, nil, nil, ErrInvalidHeaderType
+	}
+
+	if !payload.hasAudience(opt.Audience) ||
 		!payload.checkStringClaim("iss", opt.Issuer) ||
 		!payload.checkStringClaim("sub", opt.Subject) {
-		return nil, nil, ErrInvalidReservedClaim
+		return nil, nil, nil, ErrInvalidReservedClaim
+	}
+
+	if err = payload.checkClaims(opt.Claims, opt.RequireExpiration); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err = payload.checkIssuedAt(now, opt.ClockTolerance, opt.MaxAge); err != nil {
+		return nil, nil, nil, err
 	}
 
 	if !opt.IngoreExpiration {
-		if ok := payload.checkExpiration(opt.ClockTolerance); !ok {
-			return nil, nil, ErrTokenExpired
+		if opt.AbsoluteExpiration {
+			ok = payload.checkAbsoluteExpiration(now, opt.ClockTolerance)
+		} else {
+			ok = payload.checkExpiration(now, opt.ClockTolerance)
+		}
+
+		if !ok {
+			return nil, nil, nil, ErrTokenExpired
+		}
+	}
+
+	if opt.Revoker != nil {
+		if ok, err = opt.Revoker.IsRevoked(tokenID(pt.signature, payload)); err != nil {
+			return nil, nil, nil, err
 		}
+
+		if ok {
+			return nil, nil, nil, ErrTokenRevoked
+		}
+	}
+
+	if opt.SubjectCutoff != nil {
+		if err = payload.checkSubjectCutoff(opt.SubjectCutoff); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if err = runValidators(ctx, opt.Validators, header, payload); err != nil {
+		return nil, nil, nil, err
 	}
 
 	return
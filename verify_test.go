@@ -59,6 +59,25 @@ func TestVerfiy(t *testing.T) {
 		assert.Equal(ErrInvalidSignature, err)
 	})
 
+	t.Run("Should verify RSA sig with public key", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+		assert.Nil(err)
+
+		token, err := Sign(custom, key, &SignOption{Algorithm: RS256, ExpiresIn: time.Minute})
+
+		assert.Nil(err)
+
+		_, payload, err := Verify(token, &key.PublicKey, &VerifyOption{Algorithm: RS256})
+
+		assert.Nil(err)
+		assert.Equal(custom["test1k"], payload["test1k"])
+
+		_, _, err = Verify(token, "key", &VerifyOption{Algorithm: RS256})
+
+		assert.Equal(ErrInvalidSignature, err)
+	})
+
 	t.Run("Should return ErrInvalidReservedClaim when aud is miss-match", func(t *testing.T) {
 		token, err := Sign(custom, "key", signOpt)
 
@@ -74,6 +93,20 @@ func TestVerfiy(t *testing.T) {
 		assert.Equal(ErrInvalidReservedClaim, err)
 	})
 
+	t.Run("Should accept aud array containing the audience", func(t *testing.T) {
+		token, err := Sign(Payload{"aud": []string{"a", "testAudience"}}, "key", &SignOption{ExpiresIn: time.Minute})
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{Audience: "testAudience"})
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{Audience: "b"})
+
+		assert.Equal(ErrInvalidReservedClaim, err)
+	})
+
 	t.Run("Should return ErrInvalidReservedClaim when iss is miss-match", func(t *testing.T) {
 		token, err := Sign(custom, "key", signOpt)
 
@@ -151,6 +184,70 @@ func TestVerfiy(t *testing.T) {
 		assert.Equal(nil, err)
 	})
 
+	t.Run("Should return ErrTokenTooOld when token is older than MaxAge", func(t *testing.T) {
+		token, err := Sign(custom, "key", &SignOption{
+			ExpiresIn: time.Hour,
+			Clock:     func() time.Time { return time.Now().Add(-time.Minute) },
+		})
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{MaxAge: 30 * time.Second})
+
+		assert.Equal(ErrTokenTooOld, err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{MaxAge: 2 * time.Minute})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should return ErrTokenIssuedInFuture when iat is in the future", func(t *testing.T) {
+		token, err := Sign(custom, "key", &SignOption{
+			ExpiresIn: time.Hour,
+			Clock:     func() time.Time { return time.Now().Add(time.Minute) },
+		})
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", nil)
+
+		assert.Equal(ErrTokenIssuedInFuture, err)
+
+		_, _, err = Verify(token, "key", &VerifyOption{ClockTolerance: 2 * time.Minute})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should accept the given types", func(t *testing.T) {
+		token, err := Sign(custom, "key", &SignOption{ExpiresIn: time.Minute, Type: "at+jwt"})
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", nil)
+
+		assert.Equal(ErrInvalidHeaderType, err)
+
+		header, _, err := Verify(token, "key", &VerifyOption{Types: []string{"application/at+jwt"}})
+
+		assert.Nil(err)
+		assert.Equal("at+jwt", header["typ"])
+	})
+
+	t.Run("Should accept token without typ when AllowMissingType", func(t *testing.T) {
+		token, err := Sign(custom, "key", &SignOption{ExpiresIn: time.Minute, OmitType: true})
+
+		assert.Nil(err)
+
+		_, _, err = Verify(token, "key", nil)
+
+		assert.Equal(ErrInvalidHeaderType, err)
+
+		header, _, err := Verify(token, "key", &VerifyOption{AllowMissingType: true})
+
+		assert.Nil(err)
+		assert.NotContains(header, "typ")
+	})
+
 	t.Run("Should return original header and paylaod", func(t *testing.T) {
 		token, err := Sign(custom, "key", &SignOption{
 			Algorithm: HS256,
@@ -0,0 +1,133 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// X5COption represents the options of validating the certificate chain in
+// the "x5c" header.
+type X5COption struct {
+	// Roots is the pool of trusted root certificates, the system pool will be
+	// used if it is nil.
+	Roots *x509.CertPool
+	// KeyUsages specifies the accepted extended key usages of the leaf
+	// certificate, any usage is accepted if it is empty.
+	KeyUsages []x509.ExtKeyUsage
+	// DNSName specifies the name the leaf certificate should be valid for if
+	// it is not empty.
+	DNSName string
+}
+
+// verifyX5C validates the certificate chain in the "x5c" header and the
+// optional "x5t" and "x5t#S256" thumbprints, and returns the verified chains
+// and the public key of the leaf certificate.
+func (h Header) verifyX5C(opt *X5COption, now time.Time) ([][]*x509.Certificate, crypto.PublicKey, error) {
+	values, ok := h["x5c"].([]interface{})
+
+	if !ok || len(values) == 0 {
+		return nil, nil, fmt.Errorf("%w: missing x5c", ErrInvalidX5C)
+	}
+
+	var certs []*x509.Certificate
+
+	for _, v := range values {
+		s, ok := v.(string)
+
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: certificate is not a string", ErrInvalidX5C)
+		}
+
+		der, err := base64.StdEncoding.DecodeString(s)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidX5C, err)
+		}
+
+		cert, err := x509.ParseCertificate(der)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidX5C, err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	leaf := certs[0]
+
+	for name, thumbprint := range certificateThumbprints(leaf) {
+		if v, ok := h[name]; ok && v != thumbprint {
+			return nil, nil, fmt.Errorf("%w: %s does not match the leaf certificate", ErrInvalidX5C, name)
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	keyUsages := opt.KeyUsages
+
+	if len(keyUsages) == 0 {
+		keyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageAny}
+	}
+
+	chains, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         opt.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     keyUsages,
+		DNSName:       opt.DNSName,
+	})
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidX5C, err)
+	}
+
+	return chains, leaf.PublicKey, nil
+}
+
+// marshalX5C returns the "x5c", "x5t" and "x5t#S256" headers of the chain.
+func marshalX5C(chain []*x509.Certificate) Header {
+	x5c := make([]string, len(chain))
+
+	for i, cert := range chain {
+		x5c[i] = base64.StdEncoding.EncodeToString(cert.Raw)
+	}
+
+	h := Header{"x5c": x5c}
+
+	for name, thumbprint := range certificateThumbprints(chain[0]) {
+		h[name] = thumbprint
+	}
+
+	return h
+}
+
+func certificateMatchesKey(cert *x509.Certificate, privateKey interface{}) bool {
+	signer, ok := privateKey.(crypto.Signer)
+
+	if !ok {
+		return false
+	}
+
+	public, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+
+	return ok && public.Equal(signer.Public())
+}
+
+func certificateThumbprints(cert *x509.Certificate) map[string]string {
+	sum1 := sha1.Sum(cert.Raw)
+	sum256 := sha256.Sum256(cert.Raw)
+
+	return map[string]string{
+		"x5t":      base64.RawURLEncoding.EncodeToString(sum1[:]),
+		"x5t#S256": base64.RawURLEncoding.EncodeToString(sum256[:]),
+	}
+}
@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCertificate struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestCertificate(t *testing.T, name string, parent *testCertificate, isCA bool, usages []x509.ExtKeyUsage) *testCertificate {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		ExtKeyUsage:           usages,
+	}
+
+	if isCA {
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		template.DNSNames = []string{name}
+	}
+
+	parentCert, parentKey := template, key
+
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{key: key, cert: cert}
+}
+
+func TestX5C(t *testing.T) {
+	assert := assert.New(t)
+
+	root := newTestCertificate(t, "root", nil, true, nil)
+	intermediate := newTestCertificate(t, "intermediate", root, true, nil)
+	leaf := newTestCertificate(t, "signer.example.com", intermediate, false, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+
+	token, err := Sign(Payload{"foo": "bar"}, leaf.key, &SignOption{
+		Algorithm:        RS256,
+		ExpiresIn:        time.Minute,
+		CertificateChain: []*x509.Certificate{leaf.cert, intermediate.cert},
+	})
+
+	assert.Nil(err)
+
+	t.Run("Should embed chain and thumbprints", func(t *testing.T) {
+		header, _, err := Decode(token)
+
+		assert.Nil(err)
+		assert.Equal(2, len(header["x5c"].([]interface{})))
+		assert.NotEmpty(header["x5t"])
+		assert.NotEmpty(header["x5t#S256"])
+	})
+
+	t.Run("Should return ErrInvalidKeyType when chain does not match key", func(t *testing.T) {
+		_, err := Sign(Payload{}, root.key, &SignOption{
+			Algorithm:        RS256,
+			CertificateChain: []*x509.Certificate{leaf.cert},
+		})
+
+		assert.Equal(ErrInvalidKeyType, err)
+	})
+
+	t.Run("Should verify with leaf key and return verified chain", func(t *testing.T) {
+		_, payload, chains, err := VerifyX5C(token, &VerifyOption{
+			Algorithm: RS256,
+			X5C: &X5COption{
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+				DNSName:   "signer.example.com",
+			},
+		})
+
+		assert.Nil(err)
+		assert.Equal("bar", payload["foo"])
+		assert.Equal(1, len(chains))
+		assert.Equal([]*x509.Certificate{leaf.cert, intermediate.cert, root.cert}, chains[0])
+
+		_, _, err = Verify(token, nil, &VerifyOption{Algorithm: RS256, X5C: &X5COption{Roots: roots}})
+
+		assert.Nil(err)
+	})
+
+	t.Run("Should return ErrInvalidX5C when chain is not trusted", func(t *testing.T) {
+		other := x509.NewCertPool()
+		other.AddCert(newTestCertificate(t, "other", nil, true, nil).cert)
+
+		for _, opt := range []*X5COption{
+			{Roots: other},
+			{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}},
+			{Roots: roots, DNSName: "other.example.com"},
+		} {
+			_, _, _, err := VerifyX5C(token, &VerifyOption{Algorithm: RS256, X5C: opt})
+
+			assert.True(errors.Is(err, ErrInvalidX5C))
+		}
+	})
+
+	t.Run("Should validate chain at the time of the clock", func(t *testing.T) {
+		_, _, _, err := VerifyX5C(token, &VerifyOption{
+			Algorithm:        RS256,
+			IngoreExpiration: true,
+			Clock:            func() time.Time { return time.Now().Add(2 * time.Hour) },
+			X5C:              &X5COption{Roots: roots},
+		})
+
+		assert.True(errors.Is(err, ErrInvalidX5C))
+	})
+
+	t.Run("Should return ErrInvalidX5C when x5c is missing", func(t *testing.T) {
+		token, err := Sign(Payload{}, leaf.key, &SignOption{Algorithm: RS256})
+
+		assert.Nil(err)
+
+		_, _, _, err = VerifyX5C(token, &VerifyOption{Algorithm: RS256, X5C: &X5COption{Roots: roots}})
+
+		assert.True(errors.Is(err, ErrInvalidX5C))
+	})
+
+	t.Run("Should return ErrInvalidX5C when thumbprint does not match", func(t *testing.T) {
+		header := marshalX5C([]*x509.Certificate{leaf.cert, intermediate.cert})
+		header["alg"] = RS256
+		header["typ"] = "JWT"
+		header["x5t"] = "invalid"
+
+		headerJSON, err := json.Marshal(header)
+
+		assert.Nil(err)
+
+		content := base64.StdEncoding.EncodeToString(headerJSON) + "." +
+			base64.StdEncoding.EncodeToString([]byte(`{"iat":0}`))
+
+		signature, err := algImpMap[RS256].sign([]byte(content), leaf.key)
+
+		assert.Nil(err)
+
+		token := []byte(content + "." + base64.StdEncoding.EncodeToString(signature))
+
+		_, _, _, err = VerifyX5C(token, &VerifyOption{
+			Algorithm:        RS256,
+			IngoreExpiration: true,
+			X5C:              &X5COption{Roots: roots},
+		})
+
+		assert.True(errors.Is(err, ErrInvalidX5C))
+	})
+}